@@ -0,0 +1,108 @@
+//go:build js && wasm
+package main
+
+import (
+	"fmt"
+	"syscall/js"
+
+	"cyokozai/explorer-wasmap/solver"
+)
+
+// autoRun is a startAuto loop: a setInterval timer stepping the instance at handle.
+type autoRun struct {
+	timer js.Value
+	tick  js.Func
+}
+
+// autoRuns holds the running loops by handle.
+var autoRuns = map[int]*autoRun{}
+
+// startAuto(intervalMs, onStep, handle?) or startAuto(intervalMs, onStep, options, handle?) -> {ok}
+// Steps the instance every intervalMs (default 16) on a JS timer and calls onStep with
+// each stepACO result, so the page only has to draw. Paused instances are skipped
+// (see pauseACO). The loop stops itself after the step that reports converged, or with an
+// error result once an edge weight is no longer positive (see setEdgeWeight).
+// options: {traceAnts, delta, deltaThreshold, transfer} as in stepACO. Starting again replaces the running loop.
+// Not reachable through handleMessage: callbacks cannot cross postMessage.
+func startAutoWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 || args[1].Type() != js.TypeFunction {
+		return fail(CodeInvalidArgument, "startAuto requires intervalMs and an onStep function")
+	}
+	opts := stepOptions{DeltaThreshold: solver.DeltaThreshold}
+	handleIndex := 2
+	if len(args) > 2 && args[2].Type() != js.TypeNumber {
+		if err := decodeArg(args[2], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing auto options: "+err.Error())
+		}
+		if err := checkTransferMode(opts.Transfer); err != nil {
+			return fail(CodeInvalidArgument, "parsing auto options: "+err.Error())
+		}
+		handleIndex = 3
+	}
+	handle := defaultHandle
+	if len(args) > handleIndex && args[handleIndex].Type() == js.TypeNumber {
+		handle = args[handleIndex].Int()
+	}
+	if _, err := lookupACO(args, handleIndex); err != nil {
+		return failErr(err)
+	}
+	interval := 16.0
+	if args[0].Type() == js.TypeNumber {
+		interval = args[0].Float()
+	}
+	if interval < 0 {
+		return fail(CodeInvalidArgument, fmt.Sprintf("intervalMs must be >= 0 (got %g)", interval))
+	}
+
+	stopAuto(handle)
+	onStep := args[1]
+	run := &autoRun{}
+	run.tick = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		aco, found := instances[handle]
+		if !found {
+			stopAuto(handle)
+			return nil
+		}
+		if aco.Paused {
+			return nil
+		}
+		ants := aco.Step()
+		if err := aco.StepError(); err != nil {
+			stopAuto(handle) // a negative weight was set while running
+			onStep.Invoke(failErr(err))
+			return nil
+		}
+		if aco.Convergence().Converged {
+			stopAuto(handle)
+		}
+		onStep.Invoke(stepResult(aco, ants, opts))
+		return nil
+	})
+	run.timer = js.Global().Call("setInterval", run.tick, interval)
+	autoRuns[handle] = run
+
+	return ok()
+}
+
+// stopAuto(handle?) -> {ok}
+// Stops the startAuto loop of the instance; a no-op when none is running.
+func stopAutoWrapper(this js.Value, args []js.Value) interface{} {
+	handle := defaultHandle
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		handle = args[0].Int()
+	}
+	stopAuto(handle)
+
+	return ok()
+}
+
+// stopAuto clears the timer of handle's loop and releases its callback.
+func stopAuto(handle int) {
+	run, found := autoRuns[handle]
+	if !found {
+		return
+	}
+	js.Global().Call("clearInterval", run.timer)
+	run.tick.Release()
+	delete(autoRuns, handle)
+}
@@ -0,0 +1,96 @@
+// Command acocli runs the ACO solver from the terminal, without the browser.
+//
+//	go run ./cmd/acocli -nodes 50 -iterations 200 -seed 42
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"cyokozai/explorer-wasmap/solver"
+)
+
+func main() {
+	cfg := solver.DefaultConfig()
+
+	nodes := flag.Int("nodes", 20, "number of nodes in the generated graph")
+	iterations := flag.Int("iterations", 100, "number of ACO iterations to run")
+	seed := flag.Int64("seed", 0, "random seed (time-based when omitted)")
+	flag.StringVar(&cfg.Topology, "topology", cfg.Topology, "graph topology (ring, grid, delaunay, watts-strogatz, barabasi-albert)")
+	flag.Float64Var(&cfg.Depth, "depth", cfg.Depth, "z extent of the generated graph (0 keeps it 2D)")
+	flag.BoolVar(&cfg.Torus, "torus", cfg.Torus, "wrap the generated graph's coordinate space around at its edges")
+	flag.StringVar(&cfg.Metric, "metric", cfg.Metric, "edge length metric (euclidean, manhattan, haversine)")
+	flag.BoolVar(&cfg.ConnectComponents, "connect", cfg.ConnectComponents, "join disconnected components of the generated graph with bridge edges")
+	flag.StringVar(&cfg.Mode, "mode", cfg.Mode, "problem mode (route, tsp or orienteering; orienteering needs -budget)")
+	flag.IntVar(&cfg.CandidateListSize, "candidates", cfg.CandidateListSize, "candidate list size per node (0 considers every neighbor)")
+	flag.IntVar(&cfg.AntCount, "ants", cfg.AntCount, "ants per iteration")
+	flag.IntVar(&cfg.MaxSteps, "max-steps", cfg.MaxSteps, "moves per ant before it gives up (0 uses nodes*2 per leg)")
+	flag.Float64Var(&cfg.TurnPenalty, "turn-penalty", cfg.TurnPenalty, "distance added for a full U-turn, scaled by the turn angle")
+	flag.BoolVar(&cfg.Adaptive, "adaptive", cfg.Adaptive, "tune alpha, beta and evaporation from stagnation and success rate")
+	flag.Float64Var(&cfg.Q0, "q0", cfg.Q0, "probability of taking the best-scoring edge instead of the roulette wheel")
+	flag.StringVar(&cfg.Heuristic, "heuristic", cfg.Heuristic, "ant heuristic (inverse-distance, goal-directed, degree)")
+	flag.StringVar(&cfg.RNG, "rng", cfg.RNG, "random source for the search (pcg, counter, crypto)")
+	flag.Float64Var(&cfg.Budget, "budget", cfg.Budget, "distance an ant may travel before it gives up (0 is unlimited)")
+	flag.IntVar(&cfg.TabuLength, "tabu", cfg.TabuLength, "recently visited nodes an ant avoids (0 avoids every node of the leg)")
+	flag.IntVar(&cfg.Workers, "workers", cfg.Workers, "goroutines used to construct ant tours")
+	logLevel := flag.String("log-level", solver.LogLevel(), "stderr log level (debug shows every new best path, info, warn, error)")
+	flag.Parse()
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			cfg.Seed = seed
+		}
+	})
+
+	if *nodes < 2 {
+		fmt.Fprintln(os.Stderr, "acocli: -nodes must be >= 2")
+		os.Exit(2)
+	}
+	if *iterations < 0 {
+		fmt.Fprintln(os.Stderr, "acocli: -iterations must be >= 0")
+		os.Exit(2)
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "acocli:", err)
+		os.Exit(2)
+	}
+	if err := solver.SetLogLevel(*logLevel); err != nil {
+		fmt.Fprintln(os.Stderr, "acocli:", err)
+		os.Exit(2)
+	}
+
+	aco := solver.NewACO(*nodes, cfg)
+	fmt.Printf("nodes=%d edges=%d seed=%d\n", len(aco.Graph.Nodes), len(aco.Graph.Edges), aco.Seed)
+
+	start := time.Now()
+	_, err := aco.Run(*iterations)
+	elapsed := time.Since(start)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "acocli:", err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("iterations=%d elapsed=%s\n", aco.Iteration, elapsed)
+	if rates := aco.Stats.SuccessRate; len(rates) > 0 {
+		fmt.Printf("success=%.2f\n", rates[len(rates)-1])
+	}
+	if aco.BestPath == nil {
+		fmt.Println("no path found")
+		os.Exit(1)
+	}
+	fmt.Printf("best=%.4f path=%v\n", aco.BestDist, aco.BestPath)
+	if cfg.Mode == solver.ModeOrienteering {
+		fmt.Printf("prize=%g\n", aco.BestPrize)
+	}
+
+	if cfg.Mode == solver.ModeRoute {
+		optimum, err := aco.SolveDijkstra()
+		if err != nil {
+			fmt.Println("optimum:", err)
+			return
+		}
+		fmt.Printf("optimum=%.4f gap=%.2f%%\n", optimum.Dist, (aco.BestDist-optimum.Dist)/optimum.Dist*100)
+	}
+}
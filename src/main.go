@@ -2,23 +2,102 @@
 package main
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
+	"runtime"
+	"runtime/pprof"
+	"sort"
 	"syscall/js"
+	"time"
+
+	"cyokozai/explorer-wasmap/solver"
+)
+
+// defaultHandle is the instance driven by initACO and handle-less calls.
+const defaultHandle = 0
+
+// schemaVersion is reported by getGraph, stepACO, getStats and getCapabilities. It is bumped
+// whenever one of their payloads renames or removes a field; new optional fields keep it.
+const schemaVersion = 1
+
+var (
+	instances  = map[int]*solver.ACO{}
+	nextHandle = defaultHandle + 1
+	// disposed remembers handles released with dispose so later calls get a clear error.
+	disposed = map[int]bool{}
 )
 
-var globalACO *ACO
+// exports are the js.Funcs installed on the global object, released by dispose({all: true}).
+var exports = map[string]js.Func{}
+
+// shutdown is closed by dispose({all: true}) to let main return.
+var shutdown = make(chan struct{})
 
+// Every export returns its payload on success and an error envelope
+// {ok: false, error: {code, message}} on failure (see fail in transfer.go).
+// Commands without a payload return {ok: true}.
 func main() {
-	js.Global().Set("initACO", js.FuncOf(initACOWrapper))
-	js.Global().Set("getGraph", js.FuncOf(getGraphWrapper))
-	js.Global().Set("stepACO", js.FuncOf(stepWrapper))
+	for name, command := range commands {
+		export(name, command)
+	}
+	export("runACOAsync", runACOAsyncWrapper)
+	export("startAuto", startAutoWrapper)
+	export("registerHeuristic", registerHeuristicWrapper)
+	export("on", onWrapper)
+	export("off", offWrapper)
+	export("handleMessage", handleMessageWrapper)
+
+	solver.SetLogOutput(consoleLog)
+	solver.Logf(solver.LogInfo, "WASM Initialized")
+	<-shutdown
+}
+
+// consoleLog routes solver log messages to console.debug/info/warn/error.
+func consoleLog(level, message string) {
+	js.Global().Get("console").Call(level, message)
+}
+
+// setLogLevel(level) -> {ok}
+// level: "debug", "info" (default), "warn" or "error"; messages below it are dropped. New best
+// paths are logged at debug, instance creation and loading at info, failed calls at error.
+func setLogLevelWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return fail(CodeInvalidArgument, "setLogLevel requires a level string")
+	}
+	if err := solver.SetLogLevel(args[0].String()); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
 
-	fmt.Println("WASM Initialized")
-	select {}
+func export(name string, fn func(this js.Value, args []js.Value) interface{}) {
+	exports[name] = js.FuncOf(fn)
+	js.Global().Set(name, exports[name])
 }
 
-// initACO(numCities)
+// initACO(numCities, options?) -> {ok}
+// options: any Config field, e.g. {antCount, alpha, beta, evaporation, mode, variant, seed}
+// (object or JSON string). obstacles: [{x, y, width, height} | {points: [[x, y], ...]}] keeps
+// generated nodes out of the walls and drops edges crossing them; getGraph().obstacles returns
+// them as polygons for drawing. depth > 0 generates a 3D graph: nodes get a z in [0, depth]
+// (grid becomes a stack of layers), edge lengths are 3D, and getGraph() reports is3D: true.
+// torus: true wraps the width x height (x depth) space around at its edges: distances take the
+// shorter way across the border, grid links its opposite edges, and getGraph() reports torus: true.
+// It cannot be combined with obstacles. connectComponents: true joins a disconnected graph
+// (generated here or by loadGraph) with one bridge edge per extra component between the closest
+// pair of nodes; getGraph marks those edges bridge: true and checkGraph lists them as addedEdges.
+// metric picks how edge lengths are measured (see loadGraph);
+// with "haversine" generated nodes sit at longitude x in [0, width], latitude y in [0, height].
+// rng picks the random source of the search (the graph always comes from seed): "pcg" (default),
+// "counter" (each ant's draws depend only on seed, iteration and ant, whatever the workers) or
+// "crypto", which ignores seed and reads crypto.getRandomValues, or options.random(bytes) when
+// given a function that fills a Uint8Array. crypto runs are not reproducible.
+// species: [{name, share, alpha?, beta?, q0?}] splits the ants into groups by share (e.g. a
+// high-beta explorer and a high-q0 exploiter); omitted values fall back to the options above.
 func initACOWrapper(this js.Value, args []js.Value) interface{} {
 	numCities := 20
 	if len(args) > 0 {
@@ -28,49 +107,1666 @@ func initACOWrapper(this js.Value, args []js.Value) interface{} {
 		numCities = 2
 	}
 
-	globalACO = NewACO(numCities)
-	fmt.Printf("Initialized ACO with %d nodes\n", numCities)
+	cfg := solver.DefaultConfig()
+	if len(args) > 1 {
+		if err := decodeArg(args[1], &cfg); err != nil {
+			return fail(CodeInvalidArgument, "parsing options: "+err.Error())
+		}
+		cfg.Entropy = jsEntropy(args[1])
+	}
+	if err := cfg.Validate(); err != nil {
+		return failErr(err)
+	}
+
+	if old, found := instances[defaultHandle]; found {
+		old.Dispose()
+	}
+	instances[defaultHandle] = solver.NewACO(numCities, cfg)
+	delete(disposed, defaultHandle)
+	solver.Logf(solver.LogInfo, "Initialized ACO with %d nodes (seed %d)", numCities, instances[defaultHandle].Seed)
 
-	return nil
+	return ok()
 }
 
-// getGraph() -> JSON string
-func getGraphWrapper(this js.Value, args []js.Value) interface{} {
-	if globalACO == nil {
-		fmt.Println("Error: globalACO is null")
+// createACO(config?) -> {ok, handle}
+// config: {nodeCount, ...initACO options}
+func createACOWrapper(this js.Value, args []js.Value) interface{} {
+	opts := struct {
+		NodeCount int `json:"nodeCount"`
+		solver.Config
+	}{
+		NodeCount: 20,
+		Config:    solver.DefaultConfig(),
+	}
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing config: "+err.Error())
+		}
+		opts.Entropy = jsEntropy(args[0])
+	}
+	if opts.NodeCount < 2 {
+		opts.NodeCount = 2
+	}
+	if err := opts.Config.Validate(); err != nil {
+		return failErr(err)
+	}
+
+	handle := nextHandle
+	nextHandle++
+	instances[handle] = solver.NewACO(opts.NodeCount, opts.Config)
+	solver.Logf(solver.LogInfo, "Created ACO #%d with %d nodes", handle, opts.NodeCount)
+
+	return respond(struct {
+		OK     bool `json:"ok"`
+		Handle int  `json:"handle"`
+	}{OK: true, Handle: handle})
+}
+
+// loadGraph(graph, options?, handle?) -> {ok, report}
+// graph: {nodes: [{id, x, y, z?, label?, color?, meta?, cost?, prize?}], edges: [{from, to, weight?, rawDist?, directed?, capacity?, risk?}]}
+// (object or JSON string) or a GeoJSON FeatureCollection of Points and (Multi)LineStrings; lon/lat
+// are projected into the 100x100 coordinate space and shared vertices become intersections.
+// Point properties name and color become the node's label and color, the rest its meta.
+// Any nonzero z makes the graph 3D (getGraph().is3D); lengths then include z. A graph with
+// torus: true measures lengths wrapping around options.width x height (x depth).
+// label, color and meta are returned unchanged by getGraph. cost is a traversal penalty
+// added each time a path enters the node (see setNodeCost). prize is what a path collects
+// for visiting the node in options.mode "orienteering".
+// directed edges are one-way (from -> to) with their own pheromone. capacity scales how
+// fast an edge slows down with traffic when options.congestion > 0 (default 1 ant).
+// risk is a second edge cost for multi-objective routing: ants minimize weight +
+// options.riskWeight * risk + options.hopWeight per edge, and options.pareto > 0 keeps up to
+// that many non-dominated {dist, risk, hops, path} (see stepACO paretoFront).
+// A directed edge may have a negative weight for solveBellmanFord. Until every weight is positive
+// again, stepACO, runACO, runFor, runACOAsync, startAuto, sweepParams, solveDijkstra, solveAStar,
+// solveBidirectional, solveSA, stepGA, solveBaseline, solveAllPairs and getCentrality fail with
+// invalid_argument, and every compareSolvers row but bellman-ford carries that error.
+// Edges without a weight or rawDist are measured with options.metric: "euclidean" (default),
+// "manhattan", or "haversine", which reads x as longitude and y as latitude in degrees and gives
+// great-circle kilometres (GeoJSON input is projected to the plane first, so keep euclidean there).
+// Edges without a weight get their coordinate length scaled by options.normalization
+// ("extent" divides by the graph's bounding-box diagonal, "space" by the width x height (x depth)
+// diagonal, "none" keeps the raw length).
+// Replaces the instance at handle (default instance when omitted) and stops its startAuto loop.
+// report is the checkGraph diagnosis of the loaded graph with the default route (node 0 to the
+// last node): check report.reachable before running a search that cannot succeed.
+func loadGraphWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return fail(CodeInvalidArgument, "loadGraph requires a graph argument")
+	}
+	var raw json.RawMessage
+	if err := decodeArg(args[0], &raw); err != nil {
+		return fail(CodeInvalidArgument, "parsing graph: "+err.Error())
+	}
+	graph, err := solver.DecodeGraph(raw)
+	if err != nil {
+		return fail(CodeInvalidArgument, "parsing graph: "+err.Error())
+	}
+
+	cfg := solver.DefaultConfig()
+	if len(args) > 1 {
+		if err := decodeArg(args[1], &cfg); err != nil {
+			return fail(CodeInvalidArgument, "parsing options: "+err.Error())
+		}
+		cfg.Entropy = jsEntropy(args[1])
+	}
+	if err := cfg.Validate(); err != nil {
+		return failErr(err)
+	}
+
+	handle := defaultHandle
+	if len(args) > 2 && args[2].Type() == js.TypeNumber {
+		handle = args[2].Int()
+		if _, err := lookupACO(args, 2); err != nil {
+			return failErr(err)
+		}
+	}
+
+	aco, err := solver.NewACOFromGraph(graph, cfg)
+	if err != nil {
+		return failErr(err)
+	}
+	releaseInstance(handle) // stops its startAuto loop too
+	instances[handle] = aco
+	delete(disposed, handle)
+	solver.Logf(solver.LogInfo, "Loaded graph with %d nodes and %d edges", len(aco.Graph.Nodes), len(aco.Graph.Edges))
+
+	return respond(struct {
+		OK     bool               `json:"ok"`
+		Report solver.GraphReport `json:"report"`
+	}{OK: true, Report: aco.CheckGraph()})
+}
+
+// checkGraph(handle?) -> JSON string (or object) {reachable, components, isolated, unreachable, duplicateEdges, addedEdges}
+// reachable tells whether the current start can reach a goal through the waypoints in order
+// (every node in tsp mode), following one-way edges. components lists the node ids of each
+// connected component ignoring edge direction, largest first; isolated lists nodes without
+// edges and unreachable the nodes the start cannot reach. duplicateEdges lists the [from, to]
+// edges loadGraph dropped because they repeat an earlier edge. addedEdges lists the [from, to]
+// bridges options.connectComponents added to join the components.
+func checkGraphWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(aco.CheckGraph())
+}
+
+// getGraphMetrics(handle?) -> JSON string (or object) {nodeCount, edgeCount, averageDegree,
+// degreeDistribution, diameter, radius, exact, clustering, components}
+// Edge direction is ignored. degreeDistribution[k] counts the nodes with k neighbors.
+// diameter and radius are the largest and smallest eccentricity in hops, each node's measured
+// within its own component; above 500 nodes they are estimated from 32 sampled nodes and exact
+// is false. clustering is the average local clustering coefficient.
+func getGraphMetricsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(aco.GraphMetrics())
+}
+
+// getMinimumSpanningTree(handle?) -> JSON string (or object) {edges, weight, rawDist, pheromoneShare}
+// Kruskal over edge weights, ignoring edge direction; a disconnected graph yields a spanning
+// forest. edges are indices into graph.edges (the same order as getPheromones), sorted
+// ascending. pheromoneShare is the fraction of the total pheromone lying on the tree edges.
+func getMinimumSpanningTreeWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(aco.MinimumSpanningTree())
+}
+
+// getCentrality(handle?) -> JSON string (or object) {edges, max, exact, pheromoneCorrelation}
+// Edge betweenness (Brandes): edges[i] sums, over every ordered node pair, the fraction of
+// shortest paths that use graph.edges[i], measured with the weights and directions the ants
+// see (undirected edges add both directions). Above 500 nodes it is extrapolated from 32
+// sampled sources and exact is false. pheromoneCorrelation is the Pearson correlation between
+// edges and the current pheromone levels (0 when either is constant).
+func getCentralityWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+
+	centrality, err := aco.EdgeCentrality()
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(centrality)
+}
+
+// getGraphDiff(sinceVersion, handle?) -> JSON string (or object) {version, full, changes: [{version, op, node?, edge?}]}
+// The edits made after sinceVersion, oldest first, so a client holding the graph from getGraph
+// can apply them instead of fetching it again. Every node or edge edit advances the version by
+// one. op is "addNode", "updateNode" (setNodeCost), "removeNode" (its edges go too and later
+// node ids shift down by one), "addEdge" (appended to graph.edges), "updateEdge"
+// (setEdgeWeight) or "removeEdge"; node and edge hold the new value, or the removed one.
+// addNode's connections follow as addEdge changes. Only the latest 1000 changes are kept: full
+// is true when sinceVersion is older than that or newer than version (for example after
+// loadState), and the client must call getGraph again.
+func getGraphDiffWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 1 || args[0].Type() != js.TypeNumber {
+		return fail(CodeInvalidArgument, "getGraphDiff requires sinceVersion")
+	}
+
+	return respond(aco.GraphDiff(args[0].Int()))
+}
+
+// saveState(handle?) -> JSON string {version, config, graph, pheromones, bestDist, bestPath, rng, iteration, ...}
+// Always a string (regardless of the transfer mode) so it can go straight into localStorage.
+func saveStateWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+	snapshot, err := aco.Snapshot()
+	if err != nil {
+		return fail(CodeInternal, err.Error())
+	}
+	blob, err := json.Marshal(snapshot)
+	if err != nil {
+		return fail(CodeMarshalFailed, err.Error())
+	}
+
+	return string(blob)
+}
+
+// loadState(blob, handle?) -> {ok}
+// blob: a saveState result (JSON string or parsed object). Replaces the instance at
+// handle (default instance when omitted) and stops its startAuto loop; later steps continue
+// exactly where it left off.
+func loadStateWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return fail(CodeInvalidArgument, "loadState requires a saved state")
+	}
+	var snapshot solver.Snapshot
+	if err := decodeArg(args[0], &snapshot); err != nil {
+		return fail(CodeInvalidArgument, "parsing state: "+err.Error())
+	}
+
+	handle := defaultHandle
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		handle = args[1].Int()
+		if _, err := lookupACO(args, 1); err != nil {
+			return failErr(err)
+		}
+	}
+
+	aco, err := solver.RestoreSnapshot(snapshot)
+	if err != nil {
+		return failErr(err)
+	}
+	releaseInstance(handle) // stops its startAuto loop too
+	instances[handle] = aco
+	delete(disposed, handle)
+	solver.Logf(solver.LogInfo, "Restored ACO at iteration %d (seed %d)", aco.Iteration, aco.Seed)
+
+	return ok()
+}
+
+// exportGraph(format?, handle?) -> string document
+// format: "graphml" (default) or "dot"; edges carry weight and pheromone attributes.
+func exportGraphWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	format := solver.FormatGraphML
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		format = args[0].String()
+	}
+	doc, err := aco.ExportGraph(format)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return doc
+}
+
+// addEdge(u, v, weight?, handle?) or addEdge(u, v, options, handle?) -> {ok}
+// options: {weight, directed}; directed adds a one-way edge u -> v.
+// weight defaults to the coordinate length, scaled like the existing edges.
+func addEdgeWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 3)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 2 {
+		return fail(CodeInvalidArgument, "addEdge requires u and v")
+	}
+	var opts struct {
+		Weight   float64 `json:"weight"`
+		Directed bool    `json:"directed"`
+	}
+	if len(args) > 2 {
+		if args[2].Type() == js.TypeNumber {
+			opts.Weight = args[2].Float()
+		} else if err := decodeArg(args[2], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing edge options: "+err.Error())
+		}
+	}
+	if err := aco.AddEdge(args[0].Int(), args[1].Int(), opts.Weight, opts.Directed); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// removeEdge(u, v, handle?) -> {ok}
+// A one-way edge is only matched in its own direction (u -> v).
+func removeEdgeWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 2)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 2 {
+		return fail(CodeInvalidArgument, "removeEdge requires u and v")
+	}
+	if err := aco.RemoveEdge(args[0].Int(), args[1].Int()); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// setEdgeWeight(u, v, weight, handle?) -> {ok}
+// weight must be nonzero; negative weights are only allowed on directed edges (see loadGraph).
+func setEdgeWeightWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 3)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 3 {
+		return fail(CodeInvalidArgument, "setEdgeWeight requires u, v and weight")
+	}
+	if err := aco.SetEdgeWeight(args[0].Int(), args[1].Int(), args[2].Float()); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
 
-		return "{}"
+// setNodeCost(id, cost, handle?) -> {ok}
+// cost (>= 0) is added to a path's distance each time it enters the node,
+// and the ants' heuristic sees it as part of the incoming edge's length.
+func setNodeCostWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 2)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 2 {
+		return fail(CodeInvalidArgument, "setNodeCost requires id and cost")
+	}
+	if err := aco.SetNodeCost(args[0].Int(), args[1].Float()); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// addNode(x, y, connectTo?, handle?) -> {ok, id}
+// connectTo: array of node ids to link the new node with (default weights).
+func addNodeWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 3)
+	if err != nil {
+		return failErr(err)
 	}
-	jsonData, err := json.Marshal(globalACO.Graph)
+	if len(args) < 2 {
+		return fail(CodeInvalidArgument, "addNode requires x and y")
+	}
+	var connectTo []int
+	if len(args) > 2 {
+		if err := decodeArg(args[2], &connectTo); err != nil {
+			return fail(CodeInvalidArgument, "parsing connectTo: "+err.Error())
+		}
+	}
+
+	id, err := aco.AddNode(args[0].Float(), args[1].Float(), connectTo)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(struct {
+		OK bool `json:"ok"`
+		ID int  `json:"id"`
+	}{OK: true, ID: id})
+}
+
+// removeNode(id, handle?) -> {ok}
+// Node ids above id shift down by one (like splicing getGraph().nodes).
+func removeNodeWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
 	if err != nil {
-		fmt.Println("Error marshalling graph:", err)
+		return failErr(err)
+	}
+	if len(args) < 1 {
+		return fail(CodeInvalidArgument, "removeNode requires a node id")
+	}
+	if err := aco.RemoveNode(args[0].Int()); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// destroyACO(handle) -> {ok}
+// Frees the instance like dispose, but later calls with the handle report not_initialized.
+func destroyACOWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return fail(CodeInvalidArgument, "destroyACO requires a handle")
+	}
+	if _, err := lookupACO(args, 0); err != nil {
+		return failErr(err)
+	}
+	releaseInstance(args[0].Int())
+
+	return ok()
+}
+
+// dispose(handle?) or dispose({all: true}) -> {ok}
+// Releases the instance: stops its startAuto loop, drops its graph and pheromone
+// matrices and makes every later call with the handle fail with code "disposed"
+// (initACO brings the default instance back). A runACOAsync in flight resolves
+// with that error after its current chunk.
+// {all: true} tears down the whole module before the page re-instantiates it: every
+// instance is disposed, the exported functions are released and replaced by stubs
+// that throw, and the Go program exits.
+func disposeWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) > 0 && args[0].Type() == js.TypeObject {
+		var opts struct {
+			All bool `json:"all"`
+		}
+		if err := decodeArg(args[0], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing options: "+err.Error())
+		}
+		if opts.All {
+			disposeModule()
+			return ok()
+		}
+	}
+	handle := defaultHandle
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		handle = args[0].Int()
+	}
+	if _, err := lookupACO(args, 0); err != nil {
+		return failErr(err)
+	}
+	releaseInstance(handle)
+	disposed[handle] = true
+
+	return ok()
+}
+
+// releaseInstance stops handle's loop and frees its instance.
+func releaseInstance(handle int) {
+	stopAuto(handle)
+	if aco, found := instances[handle]; found {
+		aco.Dispose()
+		delete(instances, handle)
+	}
+}
+
+// disposeModule disposes every instance, swaps each export for a throwing stub,
+// releases the Go callbacks and lets main return.
+func disposeModule() {
+	for handle := range instances {
+		releaseInstance(handle)
+		disposed[handle] = true
+	}
+	stub := js.Global().Get("Function").New(`throw new Error("explorer-wasmap was disposed; instantiate the module again")`)
+	for name, f := range exports {
+		js.Global().Set(name, stub)
+		f.Release()
+	}
+	exports = map[string]js.Func{}
+	close(shutdown)
+}
 
-		return "{}"
+// getGraph(handle?) -> JSON string (or object, see setTransferMode) {schemaVersion, nodes, edges, mode?, is3D?, torus?, obstacles?, version}
+// version is the graph version to pass to getGraphDiff.
+func getGraphWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
 	}
 
-	return string(jsonData)
+	return respond(struct {
+		SchemaVersion int `json:"schemaVersion"`
+		solver.GraphData
+	}{schemaVersion, aco.Graph})
 }
 
-// stepACO() -> JSON string {bestDist, bestPath}
+// stepACO(handle?) or stepACO(options, handle?)
+// -> JSON string (or object) {schemaVersion, bestDist, bestRawDist, bestPath, bestPrize?, iteration, stagnation, entropy, converged, evaporation, alpha, beta, failures, topPaths?, paretoFront?, colonies?, ants?}
+// bestDist is in normalized edge weights, bestRawDist in edge rawDist (coordinate units).
+// bestPrize: in config.mode "orienteering", the prize of bestPath (the largest within config.budget).
+// evaporation, alpha, beta: the values this step used after config.evaporationSchedule and config.adaptive.
+// failures {deadEnds, stepLimited, overBudget, stuckNode}: this step's failed ants that hit a dead end,
+// config.maxSteps or config.budget, and the node most dead ends stopped at (-1 when none).
+// topPaths: up to config.topK distinct {dist, path}, shortest first.
+// paretoFront: up to config.pareto {dist, risk, hops, path} no other path beats on all three.
+// colonies: each colony's {colony, bestDist, bestPath} when config.colonies > 1.
+// options: {traceAnts} adds ants: [{path, dist, success, colony?, species?, prize?, deadEnd?, stepLimit?,
+// overBudget?, backtracks?}] for this iteration.
+// options: {delta, deltaThreshold?} adds bestChanged and pheromones {full, changes: [{edge, value}], max}:
+// changes lists the edges (getGraph().edges index) that moved more than deltaThreshold (default 0.05)
+// x max since the previous delta step, full means every edge is listed; bestPath and topPaths are
+// then only sent when bestChanged.
+// options: {transfer} overrides the setTransferMode encoding for this response.
 func stepWrapper(this js.Value, args []js.Value) interface{} {
-	if globalACO == nil {
-		return "{}"
+	opts := stepOptions{DeltaThreshold: solver.DeltaThreshold}
+	handleIndex := 0
+	if len(args) > 0 && args[0].Type() != js.TypeNumber {
+		if err := decodeArg(args[0], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing step options: "+err.Error())
+		}
+		if err := checkTransferMode(opts.Transfer); err != nil {
+			return fail(CodeInvalidArgument, "parsing step options: "+err.Error())
+		}
+		handleIndex = 1
+	}
+	aco, err := lookupACO(args, handleIndex)
+	if err != nil {
+		return failErr(err)
 	}
-	
-	globalACO.Step()
+	ants := aco.Step()
+	if err := aco.StepError(); err != nil {
+		return failErr(err)
+	}
+
+	return stepResult(aco, ants, opts)
+}
 
+// stepOptions are the stepACO options, also accepted by startAuto.
+type stepOptions struct {
+	TraceAnts      bool    `json:"traceAnts"`
+	Delta          bool    `json:"delta"`
+	DeltaThreshold float64 `json:"deltaThreshold"` // fraction of the max pheromone, default 0.05
+	Transfer       string  `json:"transfer"`
+}
+
+// stepResult is the stepACO response for the iteration that produced ants.
+func stepResult(aco *solver.ACO, ants []solver.AntResult, opts stepOptions) interface{} {
+	if opts.Delta {
+		return deltaResult(aco, ants, opts)
+	}
 	result := struct {
-		BestDist float64 `json:"bestDist"`
-		BestPath []int   `json:"bestPath"`
+		SchemaVersion int     `json:"schemaVersion"`
+		BestDist      float64 `json:"bestDist"`    // in normalized edge weights
+		BestRawDist   float64 `json:"bestRawDist"` // in edge rawDist (coordinate units)
+		BestPath      []int   `json:"bestPath"`
+		BestPrize     float64 `json:"bestPrize,omitempty"`
+		solver.Convergence
+		Failures    solver.Failures     `json:"failures"`
+		TopPaths    []solver.RankedPath `json:"topPaths,omitempty"`
+		ParetoFront []solver.ParetoPath `json:"paretoFront,omitempty"`
+		Colonies    []solver.ColonyBest `json:"colonies,omitempty"`
+		Ants        []solver.AntResult  `json:"ants,omitempty"`
 	}{
-		BestDist: globalACO.BestDist,
-		BestPath: globalACO.BestPath,
+		SchemaVersion: schemaVersion,
+		BestDist:      aco.BestDist,
+		BestRawDist:   aco.RawPathDistance(aco.BestPath),
+		BestPath:      aco.BestPath,
+		BestPrize:     aco.BestPrize,
+		Convergence:   aco.Convergence(),
+		Failures:      aco.LastFailures(),
+		TopPaths:      aco.TopPaths,
+		ParetoFront:   aco.ParetoFront,
+		Colonies:      aco.ColonyBests(),
+	}
+	if opts.TraceAnts {
+		result.Ants = ants
+	}
+
+	return respondAs(opts.Transfer, result)
+}
+
+// deltaResult is the stepACO response in delta mode: the pheromone diff against the previous
+// delta step plus the scalar fields of stepResult.
+func deltaResult(aco *solver.ACO, ants []solver.AntResult, opts stepOptions) interface{} {
+	delta := aco.Delta(opts.DeltaThreshold)
+	result := struct {
+		SchemaVersion int     `json:"schemaVersion"`
+		BestDist      float64 `json:"bestDist"`
+		BestRawDist   float64 `json:"bestRawDist"`
+		BestPrize     float64 `json:"bestPrize,omitempty"`
+		BestChanged   bool    `json:"bestChanged"`
+		BestPath      []int   `json:"bestPath,omitempty"` // only when bestChanged, as topPaths
+		solver.Convergence
+		Failures    solver.Failures       `json:"failures"`
+		Pheromones  solver.PheromoneDelta `json:"pheromones"`
+		TopPaths    []solver.RankedPath   `json:"topPaths,omitempty"`
+		ParetoFront []solver.ParetoPath   `json:"paretoFront,omitempty"`
+		Colonies    []solver.ColonyBest   `json:"colonies,omitempty"`
+		Ants        []solver.AntResult    `json:"ants,omitempty"`
+	}{
+		SchemaVersion: schemaVersion,
+		BestDist:      aco.BestDist,
+		BestRawDist:   aco.RawPathDistance(aco.BestPath),
+		BestPrize:     aco.BestPrize,
+		BestChanged:   delta.BestChanged,
+		Convergence:   aco.Convergence(),
+		Failures:      aco.LastFailures(),
+		Pheromones:    delta,
+		ParetoFront:   aco.ParetoFront,
+		Colonies:      aco.ColonyBests(),
+	}
+	if delta.BestChanged {
+		result.BestPath, result.TopPaths = aco.BestPath, aco.TopPaths
+	}
+	if opts.TraceAnts {
+		result.Ants = ants
+	}
+
+	return respondAs(opts.Transfer, result)
+}
+
+// runACO(iterations, handle?) or runACO(iterations, options, handle?)
+// -> JSON string {bestDist, bestRawDist, bestPath, history}
+// history[i] is the best distance after iteration i.
+// options: {onProgress, progressEvery} calls onProgress({iteration, bestDist, bestPath})
+// every progressEvery iterations (default 1) while the run is in progress.
+func runACOWrapper(this js.Value, args []js.Value) interface{} {
+	req, err := parseRunArgs(args)
+	if err != nil {
+		return failErr(err)
+	}
+
+	history, err := req.aco.RunWithProgress(req.iterations, req.hook)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return runResult(req.aco, history)
+}
+
+// runFor(ms, handle?) or runFor(ms, options, handle?)
+// -> JSON string {from, to, iterations, elapsedMs, history, bestDist, bestRawDist, bestPath}
+// Steps until ms of wall-clock time are used, stopping early when another step of the
+// last step's duration would overrun, so a frame can give the solver a fixed slice.
+// Iterations from..to (inclusive) were completed; at least one step always runs.
+// options: {maxIterations, onProgress, progressEvery} (progress as in runACO).
+func runForWrapper(this js.Value, args []js.Value) interface{} {
+	req, err := parseRunArgs(args)
+	if err != nil {
+		return failErr(err)
+	}
+	budget := 16.0
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		budget = args[0].Float()
+	}
+	maxIterations := 0
+	if req.options.Type() == js.TypeObject {
+		if v := req.options.Get("maxIterations"); v.Type() == js.TypeNumber {
+			maxIterations = v.Int()
+		}
+	}
+
+	run, err := req.aco.RunFor(time.Duration(budget*float64(time.Millisecond)), maxIterations, req.hook)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(struct {
+		solver.TimedRun
+		bestResult
+	}{TimedRun: run, bestResult: newBestResult(req.aco)})
+}
+
+// runACOAsync(iterations, handle?) or runACOAsync(iterations, options, handle?)
+// -> Promise resolving to the runACO result, or rejecting with the error envelope.
+// options: the runACO options plus {chunkSize}, the iterations run between yields to
+// the event loop (default 10). Other calls, including ones on the same handle, may run
+// between chunks.
+func runACOAsyncWrapper(this js.Value, args []js.Value) interface{} {
+	req, err := parseRunArgs(args)
+	if err != nil {
+		return js.Global().Get("Promise").Call("reject", failErr(err))
+	}
+	chunkSize := 10
+	if req.options.Type() == js.TypeObject {
+		if v := req.options.Get("chunkSize"); v.Type() == js.TypeNumber {
+			chunkSize = v.Int()
+		}
+	}
+	if chunkSize < 1 {
+		return js.Global().Get("Promise").Call("reject", fail(CodeInvalidArgument, fmt.Sprintf("chunkSize must be >= 1 (got %d)", chunkSize)))
+	}
+
+	return newPromise(func() interface{} {
+		history := make([]float64, 0, req.iterations)
+		for len(history) < req.iterations {
+			if req.aco.Disposed() {
+				return fail(CodeDisposed, "ACO instance was disposed during runACOAsync")
+			}
+			n := min(chunkSize, req.iterations-len(history))
+			chunk, err := req.aco.RunWithProgress(n, req.hook) // a weight may have gone negative since the last chunk
+			if err != nil {
+				return failErr(err)
+			}
+			history = append(history, chunk...)
+			yieldToEventLoop()
+		}
+		return runResult(req.aco, history)
+	})
+}
+
+// on(eventName, callback, handle?) -> {ok}
+// Calls callback({event, iteration, bestDist, bestPath, stagnation, colony?}) in the current
+// transfer mode at the end of every step (stepACO, runACO, runFor, startAuto, ...) in which the
+// event happened: "improvement" when a new best path is found, "restart" when a stagnation
+// restart fires (config.restartAfter; with config.colonies, colony tells which one) and
+// "converged" when the instance becomes converged (config.stagnationLimit; again after a
+// later improvement).
+// Callbacks run in registration order. They belong to the instance, so initACO, loadGraph and
+// loadState drop them. Not reachable through handleMessage: callbacks cannot cross postMessage.
+func onWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 2)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 2 || args[0].Type() != js.TypeString || args[1].Type() != js.TypeFunction {
+		return fail(CodeInvalidArgument, "on requires an event name and a callback function")
+	}
+	callback := args[1]
+	if err := aco.On(args[0].String(), func(e solver.Event) {
+		callback.Invoke(respond(e))
+	}); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// off(eventName?, handle?) -> {ok}
+// Removes every callback registered with on for eventName, or for all events when it is omitted.
+func offWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	name := ""
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		name = args[0].String()
+	}
+	if err := aco.Off(name); err != nil {
+		return failErr(err)
 	}
 
-	jsonData, err := json.Marshal(result)
+	return ok()
+}
+
+// runRequest holds the parsed (iterations, options?, handle?) arguments of runACO and runACOAsync.
+type runRequest struct {
+	aco        *solver.ACO
+	iterations int
+	hook       solver.ProgressHook
+	options    js.Value
+}
+
+func parseRunArgs(args []js.Value) (runRequest, error) {
+	req := runRequest{iterations: 1, options: js.Undefined()}
+	handleIndex := 1
+	if len(args) > 1 && args[1].Type() == js.TypeObject {
+		req.options = args[1]
+		handleIndex = 2
+	}
+	hook, err := progressHook(req.options)
 	if err != nil {
-		return "{}"
+		return runRequest{}, err
+	}
+	req.hook = hook
+	if req.aco, err = lookupACO(args, handleIndex); err != nil {
+		return runRequest{}, err
+	}
+	if len(args) > 0 {
+		req.iterations = args[0].Int()
+	}
+	if req.iterations < 1 {
+		req.iterations = 1
+	}
+	return req, nil
+}
+
+// runResult is the {bestDist, bestRawDist, bestPath, history} response of a run.
+func runResult(aco *solver.ACO, history []float64) interface{} {
+	return respond(struct {
+		bestResult
+		History []float64 `json:"history"`
+	}{bestResult: newBestResult(aco), History: history})
+}
+
+// bestResult is the instance's global best, shared by the run responses.
+type bestResult struct {
+	BestDist    float64 `json:"bestDist"`
+	BestRawDist float64 `json:"bestRawDist"`
+	BestPath    []int   `json:"bestPath"`
+}
+
+func newBestResult(aco *solver.ACO) bestResult {
+	return bestResult{
+		BestDist:    aco.BestDist,
+		BestRawDist: aco.RawPathDistance(aco.BestPath),
+		BestPath:    aco.BestPath,
+	}
+}
+
+// setRoute(start, goal, resetPheromones?, handle?) -> {ok}
+func setRouteWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 3)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 2 {
+		return fail(CodeInvalidArgument, "setRoute requires start and goal")
+	}
+	resetPheromones := len(args) > 2 && args[2].Truthy()
+
+	if err := aco.SetRoute(args[0].Int(), args[1].Int(), resetPheromones); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// resetACO(keepGraph?, handle?) -> {ok}
+// Clears pheromones and the best path; regenerates the graph unless keepGraph is true.
+func resetACOWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	keepGraph := len(args) > 0 && args[0].Truthy()
+	aco.Reset(keepGraph)
+
+	return ok()
+}
+
+// pauseACO(handle?) -> {ok}
+func pauseACOWrapper(this js.Value, args []js.Value) interface{} {
+	return setPaused(args, true)
+}
+
+// resumeACO(handle?) -> {ok}
+func resumeACOWrapper(this js.Value, args []js.Value) interface{} {
+	return setPaused(args, false)
+}
+
+func setPaused(args []js.Value, paused bool) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+	aco.Paused = paused
+
+	return ok()
+}
+
+// setWaypoints(ids, handle?) -> {ok}
+// ids: node ids the route must visit in order between start and goal
+// (array or JSON string); an empty array or null clears them. Route mode only (not tsp or orienteering).
+func setWaypointsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	var waypoints []int
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &waypoints); err != nil {
+			return fail(CodeInvalidArgument, "parsing waypoints: "+err.Error())
+		}
+	}
+	if err := aco.SetWaypoints(waypoints); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// setGoals(ids, handle?) -> {ok}
+// ids: goal node ids (array or JSON string); an ant succeeds on reaching any of them.
+// The first id becomes the reported goal. Several goals are route mode only.
+func setGoalsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	var goals []int
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &goals); err != nil {
+			return fail(CodeInvalidArgument, "parsing goals: "+err.Error())
+		}
+	}
+	if err := aco.SetGoals(goals); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// setParams(params, handle?) -> {ok}
+// params: any initACO option to change from the next step on, e.g. {antCount, maxSteps, alpha,
+// beta, evaporation, q0} (object or JSON string); omitted fields keep their current value. maxSteps
+// caps each ant's moves (0: nodeCount * 2 per leg); q0 (0..1) is the chance an ant takes the
+// best-scoring edge instead of spinning the roulette wheel. Options that shape the graph or problem
+// (topology, averageDegree, density, width, height, depth, torus, metric, obstacles,
+// normalization, connectComponents, oneWayRatio, mode, colonies, seed) cannot change and fail
+// with invalid_argument.
+func setParamsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) == 0 {
+		return fail(CodeInvalidArgument, "setParams requires a params object")
+	}
+	cfg := aco.Config
+	if err := decodeArg(args[0], &cfg); err != nil {
+		return fail(CodeInvalidArgument, "parsing params: "+err.Error())
+	}
+	if err := aco.SetParams(cfg); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// registerHeuristic(name, fn) -> {ok}
+// Registers fn as an ant heuristic that any instance can select with config.heuristic = name
+// (initACO or setParams). fn(from, to, dist, targets) returns the desirability of moving
+// from -> to, which is raised to beta; targets are the current leg's destinations (empty in
+// tsp mode). Non-positive or non-numeric results never get chosen. Registering a name again
+// replaces the function; the built-in "inverse-distance", "goal-directed" and "degree" cannot
+// be replaced. The callback runs for every candidate edge, so keep it cheap.
+// Not reachable through handleMessage: callbacks cannot cross postMessage.
+func registerHeuristicWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 || args[0].Type() != js.TypeString || args[1].Type() != js.TypeFunction {
+		return fail(CodeInvalidArgument, "registerHeuristic requires a name and a function")
+	}
+	fn := args[1]
+	err := solver.RegisterHeuristic(args[0].String(), func(*solver.ACO) solver.HeuristicFunc {
+		return func(from int, nb solver.Neighbor, targets []int) float64 {
+			jsTargets := make([]interface{}, len(targets))
+			for i, t := range targets {
+				jsTargets[i] = t
+			}
+			v := fn.Invoke(from, nb.To, nb.Dist, jsTargets)
+			if v.Type() != js.TypeNumber || !(v.Float() > 0) {
+				return 0
+			}
+			return v.Float()
+		}
+	})
+	if err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// getState(handle?) -> JSON string {seed, nodeCount, edgeCount, start, goal, goals?, waypoints?, paused, bestDist, bestPath, config}
+func getStateWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(aco.State())
+}
+
+// getStats(handle?) -> JSON string {schemaVersion, iteration, bestHistory[], successRate[], avgDist[], avgHops[],
+// entropy[], branching[], diversity[], deadEnds[], stuckNodes[], stepLimited[], overBudget[],
+// backtracks[], restarts?, pheromone: {min, max, mean}}
+// entropy is the normalized pheromone entropy after each step; branching the λ-branching
+// factor (λ = 0.05: mean number of edges per node whose pheromone is within the top 95% of
+// that node's range); diversity 1 - (edges two ants share / edges per ant) over the step's
+// ants, 0 when they all took the same path. Lower values mean more exploitation.
+// deadEnds counts the ants of each step that ran out of unvisited neighbors and stuckNodes the
+// node most of them stopped at (-1 when none did), which points at the sparse spot to fix;
+// stepLimited counts the ants that ran out of config.maxSteps, overBudget those
+// that went past config.budget, backtracks the dead ends they retreated from with
+// config.construction "backtrack" (route mode only).
+// restarts lists the {iteration, bestDist, colony?} of each stagnation restart (config.restartAfter).
+func getStatsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(struct {
+		SchemaVersion int `json:"schemaVersion"`
+		solver.Stats
+	}{schemaVersion, aco.GetStats()})
+}
+
+// getVisitCounts(handle?) -> JSON string (or object) {iteration, total, maxIteration, maxTotal}
+// Ant visits per node (indexed by node id, failed ants included): iteration for the last step,
+// total summed since the instance was created or reset. The max fields scale a heatmap.
+// Adding or removing nodes keeps the counts lined up with the node ids; saveState keeps total.
+func getVisitCountsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(aco.VisitCounts())
+}
+
+// getCapabilities() -> JSON string (or object) {schemaVersion, commands, transferModes, modes,
+// variants, topologies, metrics, normalizations, constructions, evaporationSchedules,
+// exchangeModes, rngs, heuristics, astarHeuristics, baselines, extractMethods, compareSolvers,
+// exportFormats}
+// Lets a client feature-detect across WASM builds. commands lists the installed exports
+// (handleMessage reaches all of them except runACOAsync and handleMessage itself); the other
+// lists are the names accepted by the matching option or argument, each sorted, with
+// heuristics including the ones added by registerHeuristic.
+func getCapabilitiesWrapper(this js.Value, args []js.Value) interface{} {
+	commandNames := make([]string, 0, len(exports))
+	for name := range exports {
+		commandNames = append(commandNames, name)
+	}
+	sort.Strings(commandNames)
+
+	return respond(struct {
+		SchemaVersion int      `json:"schemaVersion"`
+		Commands      []string `json:"commands"`
+		TransferModes []string `json:"transferModes"`
+		solver.Capabilities
+	}{schemaVersion, commandNames, []string{TransferJSON, TransferMsgPack, TransferObject}, solver.SupportedCapabilities()})
+}
+
+// getHistory(handle?) -> JSON string [{iteration, dist, path, time}]
+// Every improvement of the best path so far, oldest first; time is a Unix timestamp in
+// milliseconds (new Date(time)). Cleared by resetACO and by route changes that reset the best.
+func getHistoryWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+	history := aco.History
+	if history == nil {
+		history = []solver.Improvement{}
+	}
+
+	return respond(history)
+}
+
+// setRecording(options, handle?) -> {ok}
+// options: {record, recordPheromones} as in initACO. record is how many of the latest
+// iterations stepping keeps for getReplay (0 stops recording and drops what was kept);
+// shrinking keeps the newest frames.
+func setRecordingWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	opts := struct {
+		Record           int  `json:"record"`
+		RecordPheromones bool `json:"recordPheromones"`
+	}{aco.Config.Record, aco.Config.RecordPheromones}
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing options: "+err.Error())
+		}
+	}
+	if err := aco.SetRecording(opts.Record, opts.RecordPheromones); err != nil {
+		return failErr(err)
+	}
+
+	return ok()
+}
+
+// getReplay(from?, to?, handle?) -> JSON string {first, last, frames: [{iteration, bestDist, bestPath, pheromone?}]}
+// Recorded iterations between from and to (inclusive, null/undefined for an open end), oldest
+// first; first and last give the range still in the buffer (see config.record). pheromone is
+// {min, max, mean} when config.recordPheromones. Recordings are not part of saveState.
+func getReplayWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 2)
+	if err != nil {
+		return failErr(err)
+	}
+	from, to := 0, math.MaxInt
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		from = args[0].Int()
+	}
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		to = args[1].Int()
+	}
+	replay, err := aco.GetReplay(from, to)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(replay)
+}
+
+// cpuProfile receives the CPU profile between startProfile and stopProfile (nil when none runs).
+var cpuProfile *bytes.Buffer
+
+// startProfile() -> {ok}
+// Starts an in-memory CPU profile; stopProfile returns it. Fails with invalid_argument while
+// another profile is running.
+func startProfileWrapper(this js.Value, args []js.Value) interface{} {
+	if cpuProfile != nil {
+		return fail(CodeInvalidArgument, "a profile is already running (call stopProfile first)")
+	}
+	buf := &bytes.Buffer{}
+	if err := pprof.StartCPUProfile(buf); err != nil {
+		return fail(CodeInternal, err.Error())
+	}
+	cpuProfile = buf
+
+	return ok()
+}
+
+// stopProfile() -> JSON string (or object) {ok, cpu, heap}
+// Stops the profile started by startProfile. cpu and heap are base64-encoded pprof profiles
+// (the heap one covers allocations since the module started); decode them to files and open
+// with go tool pprof, e.g. base64 -d > cpu.pprof && go tool pprof main.wasm cpu.pprof.
+// The CPU profile may hold no samples where the runtime cannot profile (the browser's js/wasm).
+func stopProfileWrapper(this js.Value, args []js.Value) interface{} {
+	if cpuProfile == nil {
+		return fail(CodeInvalidArgument, "no profile is running (call startProfile first)")
+	}
+	pprof.StopCPUProfile()
+	cpu := cpuProfile
+	cpuProfile = nil
+
+	var heap bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		return fail(CodeInternal, err.Error())
+	}
+
+	return respond(struct {
+		OK   bool   `json:"ok"`
+		CPU  string `json:"cpu"`
+		Heap string `json:"heap"`
+	}{OK: true, CPU: base64.StdEncoding.EncodeToString(cpu.Bytes()), Heap: base64.StdEncoding.EncodeToString(heap.Bytes())})
+}
+
+// getMemoryStats(options?) -> JSON string
+// {heapAlloc, heapSys, heapObjects, totalAlloc, sys, numGC, pauseTotalMs, lastPauseMs, instances}
+// Byte counts of the Go heap inside the WASM module. instances is the number of live
+// ACO handles, so a heapAlloc that keeps growing with it unchanged points at a leak.
+// options: {gc: true} runs a collection first so heapAlloc reflects only live data.
+func getMemoryStatsWrapper(this js.Value, args []js.Value) interface{} {
+	var opts struct {
+		GC bool `json:"gc"`
+	}
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing options: "+err.Error())
+		}
+	}
+	if opts.GC {
+		runtime.GC()
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	lastPause := 0.0
+	if m.NumGC > 0 {
+		lastPause = float64(m.PauseNs[(m.NumGC+255)%256]) / 1e6
+	}
+
+	return respond(struct {
+		HeapAlloc    uint64  `json:"heapAlloc"`
+		HeapSys      uint64  `json:"heapSys"`
+		HeapObjects  uint64  `json:"heapObjects"`
+		TotalAlloc   uint64  `json:"totalAlloc"`
+		Sys          uint64  `json:"sys"`
+		NumGC        uint32  `json:"numGC"`
+		PauseTotalMs float64 `json:"pauseTotalMs"`
+		LastPauseMs  float64 `json:"lastPauseMs"`
+		Instances    int     `json:"instances"`
+	}{
+		HeapAlloc:    m.HeapAlloc,
+		HeapSys:      m.HeapSys,
+		HeapObjects:  m.HeapObjects,
+		TotalAlloc:   m.TotalAlloc,
+		Sys:          m.Sys,
+		NumGC:        m.NumGC,
+		PauseTotalMs: float64(m.PauseTotalNs) / 1e6,
+		LastPauseMs:  lastPause,
+		Instances:    len(instances),
+	})
+}
+
+// solveDijkstra(handle?) -> JSON string {dist, path, expanded, gap?}
+// gap is (acoBest - optimum) / optimum once the ants have found a path.
+func solveDijkstraWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+	optimum, err := aco.SolveDijkstra()
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respondWithGap(aco, optimum)
+}
+
+// solveAllPairs(sources?, targets?, options?, handle?) -> JSON string (or object)
+// {method, sources, targets, routes: [[{path, dist}]], reached}
+// Extracts the route the current pheromone favors for every source x target pair; routes[i][j]
+// goes from sources[i] to targets[j] and has path null when the pair is not connected through
+// edges with pheromone. Omitted or empty sources/targets mean every node, up to 10000 pairs.
+// options: {method: "dijkstra"} (the default) runs one search per source with edge cost
+// dist / pheromone; "greedy" follows the strongest edges as extractPheromonePath does and
+// leaves pairs where it gets stuck without a path. dist is the route's length in edge weights.
+func solveAllPairsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 3)
+	if err != nil {
+		return failErr(err)
+	}
+	var sources, targets []int
+	opts := struct {
+		Method string `json:"method"`
+	}{solver.ExtractDijkstra}
+	for i, arg := range []struct {
+		name string
+		v    interface{}
+	}{{"sources", &sources}, {"targets", &targets}, {"options", &opts}} {
+		if len(args) > i && args[i].Type() != js.TypeNumber {
+			if err := decodeArg(args[i], arg.v); err != nil {
+				return fail(CodeInvalidArgument, "parsing "+arg.name+": "+err.Error())
+			}
+		}
+	}
+	routes, err := aco.PheromoneRoutes(sources, targets, opts.Method)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(routes)
+}
+
+// extractPheromonePath(start?, goal?, handle?) -> JSON string (or object) {path, dist, reached, minPheromone}
+// Follows the highest-pheromone edge from start (default: the current start) towards goal
+// (default: the current goal), never revisiting a node, so it shows the route the colony
+// currently believes in even when no ant walked it. Ties go to the shorter edge. When every
+// neighbor was already visited it stops there with reached false and the partial path.
+// minPheromone is the weakest pheromone along the path.
+func extractPheromonePathWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 2)
+	if err != nil {
+		return failErr(err)
+	}
+	start, goal := aco.StartNode, aco.GoalNode
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		start = args[0].Int()
+	}
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		goal = args[1].Int()
+	}
+	path, err := aco.ExtractPheromonePath(start, goal)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(path)
+}
+
+// solveBellmanFord(handle?) -> JSON string {dist, path, expanded, gap?}
+// Handles negative edge weights; a negative cycle reachable from start fails with
+// code "negative_cycle" and the cycle's nodes in the message.
+func solveBellmanFordWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 0)
+	if err != nil {
+		return failErr(err)
+	}
+	optimum, err := aco.SolveBellmanFord()
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respondWithGap(aco, optimum)
+}
+
+// solveAStar(heuristic?, handle?) -> JSON string {dist, path, expanded, gap?}
+// heuristic: "euclidean" (default) or "zero"
+func solveAStarWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	heuristic := "euclidean"
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		heuristic = args[0].String()
+	}
+	optimum, err := aco.SolveAStar(heuristic)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respondWithGap(aco, optimum)
+}
+
+// solveBidirectional(heuristic?, handle?) -> JSON string {dist, path, expanded, meetings, gap?}
+// heuristic: "euclidean" (default, bidirectional A*) or "zero" (bidirectional Dijkstra).
+// meetings holds the node where the two searches met, one per leg between waypoints.
+func solveBidirectionalWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	heuristic := "euclidean"
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		heuristic = args[0].String()
+	}
+	optimum, err := aco.SolveBidirectional(heuristic)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(struct {
+		solver.BidirectionalResult
+		Gap *float64 `json:"gap,omitempty"`
+	}{BidirectionalResult: optimum, Gap: optimalityGap(aco, optimum.Dist)})
+}
+
+// solveSA(config?, handle?) -> JSON string {dist, path, iterations, accepted, finalTemp, history}
+// config: {iterations, initialTemp, cooling, seed?} (object or JSON string); initialTemp is
+// relative to the first random route's length. Route mode only; the ants are left untouched.
+func solveSAWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	cfg := solver.DefaultSAConfig()
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &cfg); err != nil {
+			return fail(CodeInvalidArgument, "parsing config: "+err.Error())
+		}
+	}
+	result, err := aco.SolveSA(cfg)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(result)
+}
+
+// stepGA(handle?) or stepGA(config, handle?) -> JSON string {generation, bestDist, bestPath, avgDist}
+// Advances the genetic algorithm on this instance's graph by one generation.
+// config: {population, crossoverRate, mutationRate, elitism, tournament, seed?} starts a new
+// population; omit it to continue the current one. Route mode only; the ants are left untouched.
+func stepGAWrapper(this js.Value, args []js.Value) interface{} {
+	var cfg *solver.GAConfig
+	handleIndex := 0
+	if len(args) > 0 && args[0].Type() != js.TypeNumber {
+		gaConfig := solver.DefaultGAConfig()
+		if err := decodeArg(args[0], &gaConfig); err != nil {
+			return fail(CodeInvalidArgument, "parsing config: "+err.Error())
+		}
+		if args[0].Type() != js.TypeUndefined && args[0].Type() != js.TypeNull {
+			cfg = &gaConfig
+		}
+		handleIndex = 1
+	}
+	aco, err := lookupACO(args, handleIndex)
+	if err != nil {
+		return failErr(err)
+	}
+	result, err := aco.StepGA(cfg)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(result)
+}
+
+// solveBaseline(name?, config?, handle?) -> JSON string {dist, path, success, successes, improvement?}
+// name: "greedy" (default, always the nearest unvisited neighbor) or "random-walk" (uniform choice).
+// config: {attempts, seed?}; the shortest successful attempt is returned. improvement is how much
+// shorter the ACO best is, relative to the baseline. The ants are left untouched.
+func solveBaselineWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 2)
+	if err != nil {
+		return failErr(err)
+	}
+	name := solver.BaselineGreedy
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		name = args[0].String()
+	}
+	cfg := solver.DefaultBaselineConfig()
+	if len(args) > 1 {
+		if err := decodeArg(args[1], &cfg); err != nil {
+			return fail(CodeInvalidArgument, "parsing config: "+err.Error())
+		}
+	}
+	baseline, err := aco.SolveBaseline(name, cfg)
+	if err != nil {
+		return failErr(err)
+	}
+
+	result := struct {
+		solver.BaselineResult
+		Improvement *float64 `json:"improvement,omitempty"`
+	}{BaselineResult: baseline}
+	if aco.BestPath != nil && baseline.Success && baseline.Dist > 0 {
+		improvement := (baseline.Dist - aco.BestDist) / baseline.Dist
+		result.Improvement = &improvement
+	}
+
+	return respond(result)
+}
+
+// compareSolvers(config?, handle?) -> JSON string {nodeCount, edgeCount, seed,
+// results: [{solver, dist, path?, expanded?, iterations?, elapsedMs, gap?, error?}]}
+// config: {iterations (default 100, ACO iterations and GA generations), solvers?, sa?, ga?, baseline?}
+// Runs each solver ("aco", "astar", "bellman-ford", "bidirectional", "dijkstra", "ga", "greedy",
+// "random-walk", "sa"; all by default) on a fresh copy of this instance's graph and route, so
+// the instance itself is untouched. results keeps the order of solvers; gap is measured against
+// the shortest dist among them. A solver that fails has dist null and its error message.
+func compareSolversWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	cfg := solver.DefaultCompareConfig()
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &cfg); err != nil {
+			return fail(CodeInvalidArgument, "parsing config: "+err.Error())
+		}
+	}
+	comparison, err := aco.Compare(cfg)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(comparison)
+}
+
+// sweepParams(config?, handle?) -> JSON string {iterations, seed, alpha, beta, evaporation,
+// bestDist, best: {alpha, beta, evaporation, dist}}
+// config: {iterations (default 50), alpha (default [0.5, 1, 2]), beta (default [1, 2, 3, 5]),
+// evaporation (default [0.1, 0.3, 0.5])}; an empty list sweeps only the current value.
+// Runs every alpha x beta x evaporation combination (at most 1000) for iterations steps on a
+// fresh copy of this instance's graph, route and random state, so only the parameters differ
+// and the instance itself is untouched. bestDist[a][b][e] is the final best distance for
+// alpha[a], beta[b] and evaporation[e], null when no ant reached the goal; best is the
+// shortest cell, null if none succeeded.
+func sweepParamsWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	cfg := solver.DefaultSweepConfig()
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &cfg); err != nil {
+			return fail(CodeInvalidArgument, "parsing config: "+err.Error())
+		}
+	}
+	sweep, err := aco.Sweep(cfg)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(sweep)
+}
+
+// benchmark(config?) -> JSON string {iterations, elapsedMs, iterationsPerSec,
+// avgAntConstructionUs, allocs: {mallocs, totalBytes, bytesPerIteration, numGC, heapBytes}, ...}
+// config: {nodeCount, durationMs, onProgress?, progressEvery?, ...initACO options}
+// Runs a throwaway instance for durationMs of wall-clock time; no handle is created.
+// onProgress works as in runACO.
+func benchmarkWrapper(this js.Value, args []js.Value) interface{} {
+	opts := struct {
+		NodeCount  int     `json:"nodeCount"`
+		DurationMs float64 `json:"durationMs"`
+		solver.Config
+	}{
+		NodeCount:  20,
+		DurationMs: 1000,
+		Config:     solver.DefaultConfig(),
+	}
+	var hook solver.ProgressHook
+	if len(args) > 0 {
+		if err := decodeArg(args[0], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing config: "+err.Error())
+		}
+		opts.Entropy = jsEntropy(args[0])
+		if args[0].Type() == js.TypeObject {
+			var err error
+			if hook, err = progressHook(args[0]); err != nil {
+				return failErr(err)
+			}
+		}
+	}
+
+	budget := time.Duration(opts.DurationMs * float64(time.Millisecond))
+	result, err := solver.Benchmark(opts.NodeCount, opts.Config, budget, hook)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respond(result)
+}
+
+// respondWithGap serializes an exact solver result plus the ACO optimality gap.
+func respondWithGap(aco *solver.ACO, optimum solver.PathResult) interface{} {
+	return respond(struct {
+		solver.PathResult
+		Gap *float64 `json:"gap,omitempty"`
+	}{PathResult: optimum, Gap: optimalityGap(aco, optimum.Dist)})
+}
+
+// optimalityGap is how much longer the ACO best is than optimum, relative to
+// optimum; nil until the ants have found a path.
+func optimalityGap(aco *solver.ACO, optimum float64) *float64 {
+	if aco.BestPath == nil || optimum <= 0 {
+		return nil
+	}
+	gap := (aco.BestDist - optimum) / optimum
+
+	return &gap
+}
+
+// getPheromones(handle?) or getPheromones(options, handle?)
+// -> JSON string [{from, to, value, traffic, totalTraffic}] aligned with getGraph().edges
+// traffic is how many ants crossed the edge in the last step and totalTraffic the running sum
+// since the instance was created or reset (both directions of an undirected edge together).
+// options: {transfer} overrides the transfer mode for this call, as in stepACO.
+func getPheromonesWrapper(this js.Value, args []js.Value) interface{} {
+	var opts struct {
+		Transfer string `json:"transfer"`
+	}
+	handleIndex := 0
+	if len(args) > 0 && args[0].Type() != js.TypeNumber {
+		if err := decodeArg(args[0], &opts); err != nil {
+			return fail(CodeInvalidArgument, "parsing options: "+err.Error())
+		}
+		if err := checkTransferMode(opts.Transfer); err != nil {
+			return fail(CodeInvalidArgument, "parsing options: "+err.Error())
+		}
+		handleIndex = 1
+	}
+	aco, err := lookupACO(args, handleIndex)
+	if err != nil {
+		return failErr(err)
+	}
+
+	return respondAs(opts.Transfer, aco.EdgePheromones())
+}
+
+// lookupACO resolves the optional handle at args[i], falling back to the
+// default instance when it is omitted.
+func lookupACO(args []js.Value, i int) (*solver.ACO, error) {
+	handle := defaultHandle
+	if len(args) > i && args[i].Type() == js.TypeNumber {
+		handle = args[i].Int()
+	}
+	aco, found := instances[handle]
+	if !found {
+		if disposed[handle] {
+			return nil, fmt.Errorf("%w: ACO instance #%d", errDisposed, handle)
+		}
+		return nil, fmt.Errorf("%w: ACO instance #%d", errNotInitialized, handle)
+	}
+
+	return aco, nil
+}
+
+// progressHook reads {onProgress, progressEvery} from an options object.
+// onProgress receives the progress in the current transfer mode; no callback means no hook.
+func progressHook(options js.Value) (solver.ProgressHook, error) {
+	if options.Type() != js.TypeObject {
+		return solver.ProgressHook{}, nil
+	}
+	callback := options.Get("onProgress")
+	switch callback.Type() {
+	case js.TypeUndefined, js.TypeNull:
+		return solver.ProgressHook{}, nil
+	case js.TypeFunction:
+	default:
+		return solver.ProgressHook{}, fmt.Errorf("%w: onProgress must be a function, got %s", solver.ErrInvalidConfig, callback.Type())
+	}
+
+	every := 1
+	if v := options.Get("progressEvery"); v.Type() == js.TypeNumber {
+		every = v.Int()
+	}
+	if every < 1 {
+		return solver.ProgressHook{}, fmt.Errorf("%w: progressEvery must be >= 1 (got %d)", solver.ErrInvalidConfig, every)
+	}
+
+	return solver.ProgressHook{
+		Every: every,
+		Func: func(p solver.Progress) {
+			callback.Invoke(respond(p))
+		},
+	}, nil
+}
+
+// newPromise runs work on its own goroutine and resolves the returned Promise with its
+// response. Blocking in work (e.g. yieldToEventLoop) hands control back to JS.
+func newPromise(work func() interface{}) js.Value {
+	executor := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resolve := args[0]
+		go func() {
+			resolve.Invoke(work())
+		}()
+		return nil
+	})
+	defer executor.Release()
+
+	return js.Global().Get("Promise").New(executor)
+}
+
+// yieldToEventLoop blocks the calling goroutine until a zero-delay setTimeout fires,
+// letting the browser render and handle input in between.
+func yieldToEventLoop() {
+	done := make(chan struct{})
+	var resume js.Func
+	resume = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resume.Release()
+		close(done)
+		return nil
+	})
+	js.Global().Call("setTimeout", resume, 0)
+	<-done
+}
+
+// jsEntropy returns options.random as the byte source of the "crypto" rng (nil when it is not a
+// function, which leaves crypto.getRandomValues).
+func jsEntropy(options js.Value) func(p []byte) {
+	if options.Type() != js.TypeObject || options.Get("random").Type() != js.TypeFunction {
+		return nil
+	}
+	random := options.Get("random")
+	return func(p []byte) {
+		bytes := js.Global().Get("Uint8Array").New(len(p))
+		random.Invoke(bytes)
+		js.CopyBytesToGo(p, bytes)
+	}
+}
+
+// decodeArg accepts either a JS object or a JSON string and decodes it into v.
+// undefined/null leaves v untouched so callers can pre-fill defaults.
+func decodeArg(arg js.Value, v interface{}) error {
+	var raw string
+	switch arg.Type() {
+	case js.TypeUndefined, js.TypeNull:
+		return nil
+	case js.TypeString:
+		raw = arg.String()
+	case js.TypeObject:
+		raw = js.Global().Get("JSON").Call("stringify", arg).String()
+	default:
+		return fmt.Errorf("expected object or JSON string, got %s", arg.Type())
 	}
 
-	return string(jsonData)
+	return json.Unmarshal([]byte(raw), v)
 }
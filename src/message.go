@@ -0,0 +1,168 @@
+//go:build js && wasm
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+)
+
+// commands are the exports reachable through handleMessage, keyed by their global name.
+// runACOAsync, startAuto, on, off, registerHeuristic and handleMessage itself are left out:
+// Promises and callbacks cannot cross postMessage.
+var commands = map[string]func(this js.Value, args []js.Value) interface{}{
+	"initACO":                initACOWrapper,
+	"getGraph":               getGraphWrapper,
+	"getGraphDiff":           getGraphDiffWrapper,
+	"stepACO":                stepWrapper,
+	"createACO":              createACOWrapper,
+	"destroyACO":             destroyACOWrapper,
+	"dispose":                disposeWrapper,
+	"getPheromones":          getPheromonesWrapper,
+	"runACO":                 runACOWrapper,
+	"runFor":                 runForWrapper,
+	"stopAuto":               stopAutoWrapper,
+	"loadGraph":              loadGraphWrapper,
+	"checkGraph":             checkGraphWrapper,
+	"getGraphMetrics":        getGraphMetricsWrapper,
+	"getMinimumSpanningTree": getMinimumSpanningTreeWrapper,
+	"getCentrality":          getCentralityWrapper,
+	"saveState":              saveStateWrapper,
+	"loadState":              loadStateWrapper,
+	"exportGraph":            exportGraphWrapper,
+	"setRoute":               setRouteWrapper,
+	"setWaypoints":           setWaypointsWrapper,
+	"setGoals":               setGoalsWrapper,
+	"setParams":              setParamsWrapper,
+	"getState":               getStateWrapper,
+	"getStats":               getStatsWrapper,
+	"getVisitCounts":         getVisitCountsWrapper,
+	"getCapabilities":        getCapabilitiesWrapper,
+	"getHistory":             getHistoryWrapper,
+	"setRecording":           setRecordingWrapper,
+	"getReplay":              getReplayWrapper,
+	"getMemoryStats":         getMemoryStatsWrapper,
+	"startProfile":           startProfileWrapper,
+	"stopProfile":            stopProfileWrapper,
+	"addEdge":                addEdgeWrapper,
+	"removeEdge":             removeEdgeWrapper,
+	"setNodeCost":            setNodeCostWrapper,
+	"setEdgeWeight":          setEdgeWeightWrapper,
+	"addNode":                addNodeWrapper,
+	"removeNode":             removeNodeWrapper,
+	"resetACO":               resetACOWrapper,
+	"pauseACO":               pauseACOWrapper,
+	"resumeACO":              resumeACOWrapper,
+	"solveDijkstra":          solveDijkstraWrapper,
+	"solveAllPairs":          solveAllPairsWrapper,
+	"extractPheromonePath":   extractPheromonePathWrapper,
+	"solveAStar":             solveAStarWrapper,
+	"solveBellmanFord":       solveBellmanFordWrapper,
+	"solveBidirectional":     solveBidirectionalWrapper,
+	"solveSA":                solveSAWrapper,
+	"stepGA":                 stepGAWrapper,
+	"solveBaseline":          solveBaselineWrapper,
+	"benchmark":              benchmarkWrapper,
+	"compareSolvers":         compareSolversWrapper,
+	"sweepParams":            sweepParamsWrapper,
+	"setTransferMode":        setTransferModeWrapper,
+	"setLogLevel":            setLogLevelWrapper,
+	"writeGraph":             writeGraphWrapper,
+	"writePheromones":        writePheromonesWrapper,
+	"writeBestPath":          writeBestPathWrapper,
+}
+
+// messageResponse is the envelope handleMessage answers with.
+type messageResponse struct {
+	ID     json.RawMessage `json:"id"`
+	OK     bool            `json:"ok"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *apiError       `json:"error,omitempty"`
+}
+
+// handleMessage(message) -> {id, ok, result?, error?}
+// message: {id, command, args?} (object or JSON string), where command is any export name
+// in commands and args its positional arguments. id (number or string) is echoed back so a
+// Web Worker can match responses to requests:
+//
+//	onmessage = (e) => postMessage(handleMessage(e.data));
+//
+// JSON payloads are embedded in result as values; plain-text ones (exportGraph) stay strings.
+// The envelope itself follows the transfer mode like every other export; per-call
+// {transfer} options inside args are ignored so the result can still be embedded.
+func handleMessageWrapper(this js.Value, args []js.Value) interface{} {
+	var response messageResponse
+	if len(args) == 0 {
+		return respondMessage(response, fail(CodeInvalidArgument, "handleMessage requires a message"))
+	}
+
+	message := args[0]
+	if message.Type() == js.TypeString {
+		if !json.Valid([]byte(message.String())) {
+			return respondMessage(response, fail(CodeInvalidArgument, "parsing message: invalid JSON"))
+		}
+		message = js.Global().Get("JSON").Call("parse", message)
+	}
+	if message.Type() != js.TypeObject {
+		return respondMessage(response, fail(CodeInvalidArgument, fmt.Sprintf("message must be an object, got %s", message.Type())))
+	}
+
+	if id := message.Get("id"); id.Type() != js.TypeUndefined {
+		response.ID = json.RawMessage(js.Global().Get("JSON").Call("stringify", id).String())
+	}
+
+	name := message.Get("command")
+	if name.Type() != js.TypeString {
+		return respondMessage(response, fail(CodeInvalidArgument, "message.command must be a string"))
+	}
+	command, ok := commands[name.String()]
+	if !ok {
+		return respondMessage(response, fail(CodeInvalidArgument, fmt.Sprintf("unknown command %q", name.String())))
+	}
+
+	var commandArgs []js.Value
+	if list := message.Get("args"); list.Type() == js.TypeObject {
+		for i := 0; i < list.Length(); i++ {
+			commandArgs = append(commandArgs, list.Index(i))
+		}
+	}
+
+	return respondMessage(response, dispatch(command, commandArgs))
+}
+
+// dispatch runs command with its responses forced to JSON (see messageDispatch).
+func dispatch(command func(this js.Value, args []js.Value) interface{}, args []js.Value) interface{} {
+	messageDispatch = true
+	defer func() { messageDispatch = false }()
+
+	return command(js.Undefined(), args)
+}
+
+// respondMessage fills response from a command's return value (payload or error envelope).
+func respondMessage(response messageResponse, result interface{}) interface{} {
+	var raw []byte
+	switch v := js.ValueOf(result); v.Type() {
+	case js.TypeString:
+		raw = []byte(v.String())
+		if !json.Valid(raw) {
+			raw, _ = json.Marshal(v.String())
+		}
+	case js.TypeUndefined:
+		raw = []byte("null")
+	default:
+		raw = []byte(js.Global().Get("JSON").Call("stringify", v).String())
+	}
+
+	var envelope struct {
+		OK    *bool     `json:"ok"`
+		Error *apiError `json:"error"`
+	}
+	if json.Unmarshal(raw, &envelope) == nil && envelope.OK != nil && !*envelope.OK && envelope.Error != nil {
+		response.Error = envelope.Error
+		return respond(response)
+	}
+
+	response.OK = true
+	response.Result = raw
+	return respond(response)
+}
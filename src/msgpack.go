@@ -0,0 +1,281 @@
+//go:build js && wasm
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// marshalMsgPack encodes v as MessagePack, walking it by reflection with the same
+// field names, omitempty and "-" rules as encoding/json so both transfers decode to
+// the same object. Integers use the smallest fitting format, floats are float64.
+// Types with their own MarshalJSON (json.RawMessage) go through their JSON form.
+func marshalMsgPack(v interface{}) ([]byte, error) {
+	var enc msgPackEncoder
+	if err := enc.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	return enc.buf, nil
+}
+
+type msgPackEncoder struct {
+	buf []byte
+}
+
+// msgPackField is a struct field resolved to its JSON name.
+type msgPackField struct {
+	name  string
+	value reflect.Value
+}
+
+func (e *msgPackEncoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, 0xc0)
+		return nil
+	}
+	if v.Type().Implements(jsonMarshalerType) && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+		return e.encodeViaJSON(v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		return e.encode(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.encodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.encodeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.buf = append(e.buf, 0xcb)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		e.encodeString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		e.encodeLength(v.Len(), 0x90, 0xdc)
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		return e.encodeMap(v)
+	case reflect.Struct:
+		fields := msgPackFields(v, nil)
+		e.encodeLength(len(fields), 0x80, 0xde)
+		for _, f := range fields {
+			e.encodeString(f.name)
+			if err := e.encode(f.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// encodeMap writes map entries with stringified keys sorted like encoding/json does.
+func (e *msgPackEncoder) encodeMap(v reflect.Value) error {
+	keys := make([]string, 0, v.Len())
+	values := make(map[string]reflect.Value, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		key := iter.Key()
+		var name string
+		switch key.Kind() {
+		case reflect.String:
+			name = key.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			name = fmt.Sprint(key.Interface())
+		default:
+			return fmt.Errorf("msgpack: unsupported map key type %s", key.Type())
+		}
+		keys = append(keys, name)
+		values[name] = iter.Value()
+	}
+	sort.Strings(keys)
+
+	e.encodeLength(len(keys), 0x80, 0xde)
+	for _, key := range keys {
+		e.encodeString(key)
+		if err := e.encode(values[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeViaJSON encodes a json.Marshaler through its decoded JSON form.
+func (e *msgPackEncoder) encodeViaJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	return e.encode(reflect.ValueOf(generic))
+}
+
+func (e *msgPackEncoder) encodeInt(n int64) {
+	if n >= 0 {
+		e.encodeUint(uint64(n))
+		return
+	}
+	switch {
+	case n >= -32:
+		e.buf = append(e.buf, byte(n))
+	case n >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		e.buf = append(e.buf, 0xd1)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	case n >= math.MinInt32:
+		e.buf = append(e.buf, 0xd2)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	default:
+		e.buf = append(e.buf, 0xd3)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(n))
+	}
+}
+
+func (e *msgPackEncoder) encodeUint(n uint64) {
+	switch {
+	case n <= 0x7f:
+		e.buf = append(e.buf, byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	case n <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, n)
+	}
+}
+
+func (e *msgPackEncoder) encodeString(s string) {
+	switch n := len(s); {
+	case n <= 31:
+		e.buf = append(e.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xda)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdb)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+	e.buf = append(e.buf, s...)
+}
+
+// encodeLength writes an array or map header: fix is the fixarray/fixmap prefix,
+// wide the 16-bit format (the 32-bit one follows it).
+func (e *msgPackEncoder) encodeLength(n int, fix, wide byte) {
+	switch {
+	case n <= 15:
+		e.buf = append(e.buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, wide)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, wide+1)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+// msgPackFields lists the exported fields of struct v under their JSON names,
+// flattening untagged embedded structs the way encoding/json promotes them.
+func msgPackFields(v reflect.Value, fields []msgPackField) []msgPackField {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if sf.Anonymous && name == "" {
+			embedded := fv
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = msgPackFields(embedded, fields)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if strings.Contains(options, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		fields = append(fields, msgPackField{name: name, value: fv})
+	}
+
+	return fields
+}
+
+// isEmptyValue mirrors encoding/json's omitempty test.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,673 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+)
+
+func NewACO(nodeCount int, cfg Config) *ACO {
+	seed := cfg.resolveSeed()
+	randSource, src := newRand(seed)
+
+	graph := generateGraph(nodeCount, cfg, randSource)
+	// 既定の PCG なら生成に使った乱数をそのまま探索に引き継ぐ
+	if cfg.RNG != RNGPCG {
+		var search randomSource
+		randSource, search = newSearchRand(cfg, seed)
+		return newACO(graph, cfg, seed, randSource, search)
+	}
+
+	return newACO(graph, cfg, seed, randSource, src)
+}
+
+// newACO: グラフから隣接リストを構築する
+func newACO(graph GraphData, cfg Config, seed int64, randSource *rand.Rand, src randomSource) *ACO {
+	nodeCount := len(graph.Nodes)
+	graph.Mode = cfg.Mode
+
+	aco := &ACO{
+		Config:    cfg,
+		Graph:     graph,
+		Adj:       allocAdjacency(nodeCount, graph.Edges),
+		BestDist:  math.MaxFloat64,
+		BestPath:  nil,
+		Rand:      randSource,
+		randState: src,
+		Seed:      seed,
+		StartNode: 0,
+		GoalNode:  nodeCount - 1,
+	}
+	for _, e := range graph.Edges {
+		aco.link(e)
+	}
+	if cfg.Colonies > 1 {
+		aco.spawnColonies()
+	}
+
+	return aco
+}
+
+// edgeKey: 無向辺の重複判定用キー
+func edgeKey(u, v int) [2]int {
+	if u > v {
+		u, v = v, u
+	}
+	return [2]int{u, v}
+}
+
+// Step: A地点からB地点への探索 (各アリの結果を返す)
+// 結果と各アリの経路は次の Step で上書きされる作業領域なので、残す場合は複製する
+func (aco *ACO) Step() []AntResult {
+	// 負の重みではヒューリスティック 1/dist もゴールまでの距離 (Dijkstra) も成り立たない
+	if aco.stepErr = aco.CheckPositiveWeights(); aco.stepErr != nil {
+		return nil
+	}
+	if len(aco.handlers) > 0 {
+		defer aco.emitEvents(aco.converged())
+	}
+	aco.Iteration++
+	if len(aco.Colonies) > 0 {
+		return aco.stepColonies()
+	}
+	aco.adaptParams()
+	improved := false
+
+	antCount := aco.Config.AntCount
+	aco.antResults = resize(aco.antResults, antCount)
+	antResults := aco.antResults
+
+	// 1. 全てのアリがスタートからゴールを目指す (Workers > 1 なら並列に構築)
+	constructStart := time.Now()
+	walks := aco.constructAll(antCount)
+	aco.constructTime += time.Since(constructStart)
+	for k := 0; k < antCount; k++ {
+		path := walks[k].path
+		species := aco.antBuffers[k].params.species
+
+		if walks[k].outcome != outcomeSuccess {
+			antResults[k] = AntResult{Path: path, Success: false, Species: species, DeadEnd: walks[k].outcome == outcomeDeadEnd, StepLimit: walks[k].outcome == outcomeStepLimit, OverBudget: walks[k].outcome == outcomeOverBudget, Backtracks: walks[k].backtracks}
+			continue
+		}
+		if aco.Config.LocalSearch && !aco.orienteering() {
+			path = aco.localSearch(path) // ショートカットは賞金のあるノードも飛ばすので行わない
+		}
+
+		dist := aco.calculatePathDistance(path)
+		if b := aco.Config.Budget; b > 0 && dist > b {
+			// TSP でスタートへ戻る辺を足すと予算を超える場合
+			antResults[k] = AntResult{Path: path, Success: false, Species: species, OverBudget: true, Backtracks: walks[k].backtracks}
+			continue
+		}
+		prize := 0.0
+		if aco.orienteering() {
+			prize = aco.pathPrize(path)
+		}
+		antResults[k] = AntResult{Path: path, Dist: dist, Prize: prize, Success: true, Species: species, Backtracks: walks[k].backtracks}
+		aco.recordTopPath(path, dist)
+		aco.recordPareto(path, dist)
+
+		if aco.improvesBest(prize, dist) {
+			aco.BestDist = dist
+			aco.BestPrize = prize
+			bestPath := make([]int, len(path))
+			copy(bestPath, path)
+			aco.BestPath = bestPath
+			improved = true
+			Logf(LogDebug, "New Best Path Found! Distance: %.2f (Nodes: %d)", aco.BestDist, len(path))
+		}
+	}
+
+	// 2. フェロモン蒸発 (蒸発率はスケジュールに従う)
+	aco.evaporate(aco.evaporationRate())
+
+	// 3. フェロモン更新（ゴールできたアリのみ！）
+	switch aco.Config.Variant {
+	case VariantRank:
+		aco.depositRanked(antResults)
+	default:
+		for _, result := range antResults {
+			if !result.Success {
+				continue // 失敗したアリはフェロモンを残さない
+			}
+
+			aco.depositAlong(result.Path, aco.depositAmount(result.Prize, result.Dist))
+		}
+	}
+
+	// 4. エリート戦略: 大域ベスト経路を強化
+	if aco.Config.ElitistWeight > 0 && aco.BestPath != nil {
+		aco.depositAlong(aco.BestPath, aco.Config.ElitistWeight*aco.depositAmount(aco.BestPrize, aco.BestDist))
+	}
+
+	// 5. フェロモン量を [TauMin, TauMax] に収める
+	aco.clampPheromones()
+
+	// 6. 停滞カウンタ・統計の更新 (RestartAfter 回ごとに改善がなければリスタート)
+	if improved {
+		aco.Stagnation = 0
+		aco.recordImprovement()
+	} else {
+		aco.Stagnation++
+	}
+	if r := aco.Config.RestartAfter; r > 0 && aco.Stagnation > 0 && aco.Stagnation%r == 0 {
+		aco.restartPheromones()
+	}
+	aco.countUsage(antResults) // 統計の経路多様性が参照する
+	aco.recordStats(antResults)
+
+	// 7. 混雑モードなら辺ごとの通過数を次のイテレーションの重みに反映する
+	aco.applyCongestion()
+
+	return antResults
+}
+
+// clampPheromones: 全ての半辺のフェロモン量を TauMin 以上 TauMax 以下にする (0 の側は制限しない)
+func (aco *ACO) clampPheromones() {
+	tauMin, tauMax := aco.Config.TauMin, aco.Config.TauMax
+	if tauMin == 0 && tauMax == 0 {
+		return
+	}
+	if tauMax == 0 {
+		tauMax = math.Inf(1)
+	}
+	for _, row := range aco.Adj {
+		for k := range row {
+			row[k].Pheromone = min(max(row[k].Pheromone, tauMin), tauMax)
+		}
+	}
+}
+
+// depositRanked: ASrank の散布規則
+// 成功したアリを距離順 (オリエンテーリングでは賞金順) に並べ、上位 w-1 匹が (w-r)·Q/L_r を、大域ベストが w·Q/L_best を散布する
+func (aco *ACO) depositRanked(antResults []AntResult) {
+	w := aco.Config.RankWidth
+
+	ranked := make([]AntResult, 0, len(antResults))
+	for _, result := range antResults {
+		if result.Success {
+			ranked = append(ranked, result)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool { return aco.betterResult(ranked[i], ranked[j]) })
+
+	for r := 1; r < w && r <= len(ranked); r++ {
+		result := ranked[r-1]
+		aco.depositAlong(result.Path, float64(w-r)*aco.depositAmount(result.Prize, result.Dist))
+	}
+	if aco.BestPath != nil {
+		aco.depositAlong(aco.BestPath, float64(w)*aco.depositAmount(aco.BestPrize, aco.BestDist))
+	}
+}
+
+// depositAlong: 経路上の辺(両方向)に amount のフェロモンを加える
+// TSPモードでは巡回を閉じる辺も含む
+func (aco *ACO) depositAlong(path []int, amount float64) {
+	for i := 0; i < len(path)-1; i++ {
+		aco.addPheromone(path[i], path[i+1], amount)
+	}
+	if aco.Config.Mode == ModeTSP && len(path) > 1 {
+		aco.addPheromone(path[len(path)-1], path[0], amount)
+	}
+}
+
+// antOutcome: 経路構築の結末
+type antOutcome uint8
+
+const (
+	outcomeDeadEnd    antOutcome = iota // 行き止まり
+	outcomeSuccess                      // ゴール到達 (TSP は巡回完了)
+	outcomeStepLimit                    // ステップ数の上限で打ち切り
+	outcomeOverBudget                   // 距離の予算 (Config.Budget) を超えて打ち切り
+)
+
+// antWalk: 1匹分の経路構築の結果 (失敗時も途中までの経路を持つ)
+type antWalk struct {
+	path       []int
+	outcome    antOutcome
+	backtracks int // 行き止まりから引き返した回数
+}
+
+// constructSolution: スタートからゴールへの経路を探索
+// 失敗時も途中までの経路を返す (アニメーション用)
+func (aco *ACO) constructSolution(rng *rand.Rand, buf *antBuffer) antWalk {
+	return aco.constructWith(func(path []int, leg int, visited []bool) int {
+		return aco.selectNextCity(path, leg, visited, rng, buf)
+	}, aco.Config.Construction, aco.Config.TabuLength, buf)
+}
+
+// constructWith: selectNext で次のノードを選びながら経路を作る (path はここまでの経路、leg は現在の区間、-1 で行き止まり)
+// 経由地・ゴール・TSP の扱いはアリと同じ。経路探索モードでは policy (Config.Construction) に従う:
+//   - backtrack: 行き止まりで1つ前のノードへ引き返す。行き止まりのノードは訪問済みのまま残すので、
+//     同じ区間では二度と入らない (区間の始点より前には戻らない)
+//   - loop-erasure: 未訪問の隣接ノードがなければ訪問済みのノードへも戻り、最後に区間ごとにループを取り除く
+//
+// tabu > 0 なら直近 tabu 個のノードだけを訪問済みとして扱い、できたループは同じく最後に取り除く
+// buf があればその作業領域を使い回す (返す経路も buf の上にある)
+func (aco *ACO) constructWith(selectNext func(path []int, leg int, visited []bool) int, policy string, tabu int, buf *antBuffer) antWalk {
+	if aco.Config.Mode == ModeTSP {
+		policy, tabu = ConstructionSimple, 0
+	}
+	if buf == nil {
+		buf = &antBuffer{}
+	}
+	path := append(buf.path[:0], aco.StartNode)
+	buf.visited = resize(buf.visited, len(aco.Graph.Nodes))
+	visited := buf.visited
+	visited[aco.StartNode] = true
+	var noneVisited []bool // loop-erasure で訪問済みを無視して選ぶとき用
+
+	current := aco.StartNode
+	leg := 0
+	legStarts := append(buf.legStarts[:0], 0) // 各区間の始点の path 上の位置
+	backtracks := 0
+	budget := aco.Config.Budget
+	var costs []float64 // costs[i] は path[i] までに進んだ距離 (予算があるときだけ数える)
+	if budget > 0 {
+		costs = append(buf.costs[:0], 0)
+	}
+	walk := func(outcome antOutcome) antWalk {
+		buf.path, buf.legStarts, buf.costs = path, legStarts, costs
+		if policy == ConstructionLoopErasure || tabu > 0 {
+			path = eraseLoops(path, legStarts)
+		}
+		return antWalk{path, outcome, backtracks}
+	}
+
+	// 最大ステップ数制限（無限ループ防止）
+	maxSteps := aco.maxSteps()
+
+	for step := 0; step < maxSteps; step++ {
+		if aco.Config.Mode == ModeTSP {
+			// 全ノード訪問済みなら、スタートへ戻る辺があるかで成否が決まる
+			if len(path) == len(aco.Graph.Nodes) {
+				if aco.hasEdge(current, aco.StartNode) {
+					return walk(outcomeSuccess)
+				}
+				return walk(outcomeDeadEnd)
+			}
+		} else if aco.reachedLeg(leg, current) {
+			// ゴール到達チェック (いずれかのゴールでよい)
+			if leg == len(aco.Waypoints) {
+				return walk(outcomeSuccess)
+			}
+			// 経由地に着いたら次の区間へ
+			leg++
+			legStarts = append(legStarts, len(path)-1)
+			clear(visited)
+			visited[current] = true
+			continue
+		}
+
+		var next int
+		if aco.toGoal != nil {
+			// オリエンテーリング: 予算内でゴールへ戻れなくなるノードは選ばせない
+			next = aco.selectWithinBudget(selectNext, path, leg, visited, costs[len(costs)-1])
+		} else {
+			next = selectNext(path, leg, visited)
+		}
+		if next == -1 && policy == ConstructionLoopErasure {
+			// 訪問済みのノードへ戻ってループを作る (ループは最後に取り除く)
+			if noneVisited == nil {
+				buf.noneVisited = resize(buf.noneVisited, len(visited))
+				noneVisited = buf.noneVisited
+			}
+			next = selectNext(path, leg, noneVisited)
+		}
+
+		if next == -1 {
+			if policy == ConstructionBacktrack && len(path)-1 > legStarts[leg] {
+				// 行き止まりから引き返す (current は訪問済みのままにして塞ぐ)
+				path = path[:len(path)-1]
+				current = path[len(path)-1]
+				if costs != nil {
+					costs = costs[:len(costs)-1]
+				}
+				backtracks++
+				continue
+			}
+			// 行き止まり
+			return walk(outcomeDeadEnd)
+		}
+
+		if costs != nil {
+			// 予算を超えたら諦める (ループを含む経路では実際に歩いた距離で数える)
+			cost := costs[len(costs)-1] + aco.distance(current, next)
+			if len(path) > 1 {
+				cost += aco.turnCost(path[len(path)-2], current, next)
+			}
+			if cost > budget {
+				return walk(outcomeOverBudget)
+			}
+			costs = append(costs, cost)
+		}
+		path = append(path, next)
+		visited[next] = true // 訪問済みにする（ループ防止）
+		current = next
+		if tabu > 0 {
+			// タブーリストから外れたノードを再び選べるようにする (区間の始点より前は clear 済み)
+			if old := len(path) - 1 - tabu; old >= legStarts[leg] && !slices.Contains(path[old+1:], path[old]) {
+				visited[path[old]] = false
+			}
+		}
+	}
+
+	return walk(outcomeStepLimit) // ステップオーバー
+}
+
+// eraseLoops: 区間ごとに、同じノードへ戻ってきたらその間を取り除く (legStarts は各区間の始点の位置)
+// 区間の始点 (スタート・経由地) は残すので、区間をまたいだ再訪はそのまま
+func eraseLoops(path []int, legStarts []int) []int {
+	erased := make([]int, 0, len(path))
+	for i, start := range legStarts {
+		end := len(path)
+		if i+1 < len(legStarts) {
+			end = legStarts[i+1]
+		}
+		position := map[int]int{} // 区間内で残っているノードの erased 上の位置
+		for _, v := range path[start:end] {
+			if at, seen := position[v]; seen {
+				for _, removed := range erased[at+1:] {
+					delete(position, removed)
+				}
+				erased = erased[:at+1]
+				continue
+			}
+			position[v] = len(erased)
+			erased = append(erased, v)
+		}
+	}
+	return erased
+}
+
+// maxSteps: アリ1匹が進める最大ステップ数 (Config.MaxSteps、0 なら ノード数×2×区間数)
+func (aco *ACO) maxSteps() int {
+	if aco.Config.MaxSteps > 0 {
+		return aco.Config.MaxSteps
+	}
+	return len(aco.Graph.Nodes) * 2 * (len(aco.Waypoints) + 1)
+}
+
+// selectNextCity: 経路の末尾から、候補リスト内の未訪問ノードをルーレット選択する
+// 候補が全て訪問済みなら残りの隣接ノードから選ぶ
+func (aco *ACO) selectNextCity(path []int, leg int, visited []bool, rng *rand.Rand, buf *antBuffer) int {
+	current, prev := path[len(path)-1], -1 // prev は曲がり角のペナルティ用
+	if len(path) > 1 {
+		prev = path[len(path)-2]
+	}
+	var targets []int // ヒューリスティックに渡す区間の行き先 (TSP では nil)
+	if aco.Config.Mode != ModeTSP {
+		targets = aco.stepTargets[leg]
+	}
+	candidates := aco.candidates(current)
+	if next := aco.rouletteSelect(prev, current, candidates, targets, visited, rng, buf); next != -1 {
+		return next
+	}
+	if neighbors := aco.Adj[current]; len(candidates) < len(neighbors) {
+		return aco.rouletteSelect(prev, current, neighbors[len(candidates):], targets, visited, rng, buf)
+	}
+	return -1
+}
+
+func (aco *ACO) rouletteSelect(prev, current int, neighbors []Neighbor, targets []int, visited []bool, rng *rand.Rand, buf *antBuffer) int {
+	buf.probabilities = resize(buf.probabilities, len(neighbors))
+	probabilities := buf.probabilities
+	sumProb := 0.0
+	alpha, beta := buf.params.alpha, buf.params.beta
+
+	// 隣接ノードのみを候補にする
+	for k, nb := range neighbors {
+		// 未訪問
+		if !visited[nb.To] {
+			pheromone := math.Pow(nb.Pheromone, alpha)
+			eta := aco.heuristic(current, nb, targets)
+			if aco.toGoal != nil {
+				eta *= aco.Graph.Nodes[nb.To].Prize + aco.prizeFloor // 賞金の多いノードを好む
+			}
+			if turn := aco.turnCost(prev, current, nb.To); turn > 0 {
+				eta *= nb.Dist / (nb.Dist + turn) // 1/dist なら 1/(dist + 曲がるコスト) になる
+			}
+			heuristic := math.Pow(eta, beta)
+			prob := pheromone * heuristic
+			probabilities[k] = prob
+			sumProb += prob
+		}
+	}
+
+	if sumProb == 0.0 {
+		return -1
+	}
+
+	// 確率 q0 で最も評価の高い辺を選ぶ (擬似ランダム比例規則)
+	if q0 := buf.params.q0; q0 > 0 && rng.Float64() < q0 {
+		best := -1
+		for k, nb := range neighbors {
+			if !visited[nb.To] && (best == -1 || probabilities[k] > probabilities[best]) {
+				best = k
+			}
+		}
+		return neighbors[best].To
+	}
+
+	r := rng.Float64() * sumProb
+	cumulative := 0.0
+	for k, nb := range neighbors {
+		if !visited[nb.To] {
+			cumulative += probabilities[k]
+			if cumulative >= r {
+				return nb.To
+			}
+		}
+	}
+	// 誤差対策のフォールバック
+	for _, nb := range neighbors {
+		if !visited[nb.To] {
+			return nb.To
+		}
+	}
+	return -1
+}
+
+func (aco *ACO) calculatePathDistance(path []int) float64 {
+	dist := 0.0
+	for i := 0; i < len(path)-1; i++ {
+		dist += aco.distance(path[i], path[i+1])
+	}
+	// TSPモードのみ、最後にスタートに戻る距離を足す
+	if aco.Config.Mode == ModeTSP && len(path) > 1 {
+		dist += aco.distance(path[len(path)-1], path[0])
+	}
+	return dist + aco.pathTurnCost(path)
+}
+
+// RawPathDistance: 経路を辺の実距離 (RawDist) で測った長さ
+func (aco *ACO) RawPathDistance(path []int) float64 {
+	dist := 0.0
+	for i := 0; i < len(path)-1; i++ {
+		if e := aco.edgeIndex(path[i], path[i+1]); e != -1 {
+			dist += aco.Graph.Edges[e].RawDist
+		}
+	}
+	if aco.Config.Mode == ModeTSP && len(path) > 1 {
+		if e := aco.edgeIndex(path[len(path)-1], path[0]); e != -1 {
+			dist += aco.Graph.Edges[e].RawDist
+		}
+	}
+	return dist
+}
+
+// EdgePheromones: 各辺の現在のフェロモン量と通過数 (Graph.Edges と同順)
+func (aco *ACO) EdgePheromones() []EdgePheromone {
+	result := make([]EdgePheromone, len(aco.Graph.Edges))
+	for i, e := range aco.Graph.Edges {
+		result[i] = EdgePheromone{From: e.From, To: e.To}
+		if nb := aco.neighbor(e.From, e.To); nb != nil {
+			result[i].Value, result[i].Traffic, result[i].TotalTraffic = nb.Pheromone, nb.Usage, nb.Traffic
+		}
+	}
+	return result
+}
+
+// Run: Step を iterations 回繰り返し、各イテレーション後のベスト距離を返す
+// Step が進められなければ (StepError)、そこまでの履歴とそのエラーを返す
+func (aco *ACO) Run(iterations int) ([]float64, error) {
+	return aco.RunWithProgress(iterations, ProgressHook{})
+}
+
+// SetRoute: スタート・ゴールを変更し、ベスト経路をリセットする
+func (aco *ACO) SetRoute(start, goal int, resetPheromones bool) error {
+	n := len(aco.Graph.Nodes)
+	if start < 0 || start >= n || goal < 0 || goal >= n {
+		return fmt.Errorf("%w: node index out of range [0, %d): start=%d goal=%d", ErrInvalidNode, n, start, goal)
+	}
+	if start == goal {
+		return fmt.Errorf("%w: start and goal must differ (both %d)", ErrInvalidNode, start)
+	}
+
+	aco.StartNode = start
+	aco.GoalNode = goal
+	aco.Goals = nil
+	aco.clearBest()
+	if resetPheromones {
+		aco.ResetPheromones()
+	}
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.SetRoute(start, goal, resetPheromones)
+	})
+}
+
+// Reset: フェロモンとベスト経路を初期状態に戻す
+// keepGraph が false の場合は同じ設定でグラフを生成し直す (シード指定時は同じグラフになる)
+func (aco *ACO) Reset(keepGraph bool) {
+	if !keepGraph {
+		paused := aco.Paused
+		*aco = *NewACO(len(aco.Graph.Nodes), aco.Config)
+		aco.Paused = paused
+		return
+	}
+
+	aco.Iteration = 0
+	aco.Stats = IterationStats{}
+	aco.visits, aco.totalVisits = nil, nil
+	for _, row := range aco.Adj {
+		for k := range row {
+			row[k].Traffic = 0
+		}
+	}
+	aco.Adaptive = AdaptiveLevels{}
+	aco.replay = replayBuffer{}
+	aco.clearBest()
+	aco.ResetPheromones()
+	for _, colony := range aco.Colonies {
+		colony.Reset(true)
+	}
+}
+
+// Dispose: グラフ・隣接リスト・経路などを手放してインスタンスを使用不可にする
+// 実行中の非同期ループは Disposed を見て止まる
+func (aco *ACO) Dispose() {
+	for _, colony := range aco.Colonies {
+		colony.Dispose()
+	}
+	*aco = ACO{Seed: aco.Seed, disposed: true}
+}
+
+// Disposed: Dispose 済みか
+func (aco *ACO) Disposed() bool { return aco.disposed }
+
+// clearBest: ベスト経路・上位K経路・改善履歴と停滞カウンタを初期化する
+func (aco *ACO) clearBest() {
+	aco.BestDist = math.MaxFloat64
+	aco.BestPath = nil
+	aco.BestPrize = 0
+	aco.TopPaths = nil
+	aco.ParetoFront = nil
+	aco.History = nil
+	aco.Stagnation = 0
+}
+
+// ResetPheromones: 全ての辺のフェロモンを初期値に戻す
+func (aco *ACO) ResetPheromones() {
+	for i := range aco.Adj {
+		for k := range aco.Adj[i] {
+			aco.Adj[i][k].Pheromone = aco.Config.InitialPheromone
+		}
+	}
+	for _, colony := range aco.Colonies {
+		colony.ResetPheromones()
+	}
+}
+
+// restartPheromones: フェロモンを初期値に戻し、大域ベスト経路にだけ RestartBias を残す
+// ベスト経路と停滞カウンタはそのまま (収束判定は続く)
+func (aco *ACO) restartPheromones() {
+	for i := range aco.Adj {
+		for k := range aco.Adj[i] {
+			aco.Adj[i][k].Pheromone = aco.Config.InitialPheromone
+		}
+	}
+	if aco.Config.RestartBias > 0 && aco.BestPath != nil {
+		aco.depositAlong(aco.BestPath, aco.Config.RestartBias)
+	}
+	aco.clampPheromones()
+	aco.Stats.Restarts = append(aco.Stats.Restarts, Restart{Iteration: aco.Iteration, BestDist: aco.BestDist})
+}
+
+// State: 現在の状態のスナップショット (シードを含むので再現に使える)
+func (aco *ACO) State() State {
+	return State{
+		Seed:      aco.Seed,
+		NodeCount: len(aco.Graph.Nodes),
+		EdgeCount: len(aco.Graph.Edges),
+		StartNode: aco.StartNode,
+		GoalNode:  aco.GoalNode,
+		Goals:     aco.Goals,
+		Waypoints: aco.Waypoints,
+		Paused:    aco.Paused,
+		BestDist:  aco.BestDist,
+		BestPath:  aco.BestPath,
+		Config:    aco.Config,
+	}
+}
+
+// Convergence: 収束判定と指標
+// StagnationLimit イテレーション改善がなければ収束とみなす (0で判定しない)
+func (aco *ACO) Convergence() Convergence {
+	return Convergence{
+		Iteration:   aco.Iteration,
+		Stagnation:  aco.Stagnation,
+		Entropy:     aco.PheromoneEntropy(),
+		Converged:   aco.converged(),
+		Evaporation: aco.evaporationRate(),
+		Alpha:       aco.alpha(),
+		Beta:        aco.beta(),
+	}
+}
+
+// PheromoneEntropy: 辺ごとのフェロモン分布の正規化エントロピー
+// 1 = 全辺に一様 (探索的)、0 に近いほど少数の辺に集中 (収束)
+func (aco *ACO) PheromoneEntropy() float64 {
+	m := len(aco.Graph.Edges)
+	if m < 2 {
+		return 0
+	}
+
+	total := 0.0
+	for _, e := range aco.Graph.Edges {
+		total += aco.pheromone(e.From, e.To)
+	}
+	if total <= 0 {
+		return 0
+	}
+
+	entropy := 0.0
+	for _, e := range aco.Graph.Edges {
+		if p := aco.pheromone(e.From, e.To) / total; p > 0 {
+			entropy -= p * math.Log(p)
+		}
+	}
+	return entropy / math.Log(float64(m))
+}
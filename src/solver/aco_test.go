@@ -0,0 +1,70 @@
+package solver
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// Step が返す経路は作業領域なので、ACO が持ち続けるベスト・上位経路・記録はそれと共有してはいけない
+func TestStepResultsDoNotAliasState(t *testing.T) {
+	seed := int64(5)
+	cfg := DefaultConfig()
+	cfg.Seed = &seed
+	cfg.TopK = 5
+	cfg.Record = 10
+	aco := NewACO(30, cfg)
+
+	var kept []AntResult
+	for aco.BestPath == nil && aco.Iteration < 20 {
+		kept = aco.Step()
+	}
+	if aco.BestPath == nil {
+		t.Fatal("no ant reached the goal in 20 iterations")
+	}
+	bestPath := slices.Clone(aco.BestPath)
+	topPaths := make([]RankedPath, len(aco.TopPaths))
+	for i, p := range aco.TopPaths {
+		topPaths[i] = RankedPath{Dist: p.Dist, Path: slices.Clone(p.Path)}
+	}
+	replay, err := aco.GetReplay(0, aco.Iteration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := make([]ReplayFrame, len(replay.Frames))
+	for i, f := range replay.Frames {
+		frames[i] = f
+		frames[i].BestPath = slices.Clone(f.BestPath)
+	}
+
+	// 次の Step で上書きされたのと同じ状態にする
+	for _, result := range kept {
+		for i := range result.Path {
+			result.Path[i] = -1
+		}
+	}
+	if !slices.Equal(aco.BestPath, bestPath) {
+		t.Fatalf("BestPath changed with the ant buffers: %v, want %v", aco.BestPath, bestPath)
+	}
+	if !reflect.DeepEqual(aco.TopPaths, topPaths) {
+		t.Fatalf("TopPaths changed with the ant buffers: %v, want %v", aco.TopPaths, topPaths)
+	}
+	if replay, _ := aco.GetReplay(0, aco.Iteration); !reflect.DeepEqual(replay.Frames, frames) {
+		t.Fatalf("replay frames changed with the ant buffers: %v, want %v", replay.Frames, frames)
+	}
+
+	// 次の Step の後も、それまでの記録とベストは正しい経路のまま
+	aco.Step()
+	replay, _ = aco.GetReplay(0, aco.Iteration)
+	if !reflect.DeepEqual(replay.Frames[:len(frames)], frames) {
+		t.Fatalf("earlier replay frames changed after Step: %v, want %v", replay.Frames[:len(frames)], frames)
+	}
+	if slices.Contains(aco.BestPath, -1) || aco.calculatePathDistance(aco.BestPath) != aco.BestDist {
+		t.Fatalf("BestPath %v does not match BestDist %v", aco.BestPath, aco.BestDist)
+	}
+	for _, p := range aco.TopPaths {
+		if slices.Contains(p.Path, -1) || aco.calculatePathDistance(p.Path) != p.Dist {
+			t.Fatalf("top path %v does not match its dist %v", p.Path, p.Dist)
+		}
+	}
+}
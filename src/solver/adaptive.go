@@ -0,0 +1,80 @@
+package solver
+
+import "math"
+
+// パラメータの自動調整 (Config.Adaptive)
+// イテレーションの始めに、直前の改善・停滞・成功率を見て α・β・蒸発率を段階的に上げ下げする。
+// 設定値はそのまま残し、段階 (AdaptiveLevels) に応じた倍率を掛けた値を実際に使う
+// (蒸発率は残る割合 1 - ρ を倍率で割る)。
+//   改善した: 全ての段階を1つ 0 へ戻す (設定値に寄せて集中させる)
+//   AdaptiveStagnation 回以上改善しない: α を下げ蒸発率を上げる (フェロモンの偏りを崩して多様化)
+//   成功率が AdaptiveMinSuccess 未満: β を上げる (ヒューリスティックでゴールへ導く)
+//   全てのアリが成功した: 上げていた β を1段戻す
+
+const (
+	AdaptiveRate       = 0.1 // 1段あたりの倍率の変化 (段階 k で (1+AdaptiveRate)^k 倍)
+	AdaptiveMaxLevel   = 10  // 段階の上限と下限 (±)
+	AdaptiveStagnation = 10  // 多様化を始める停滞イテレーション数
+	AdaptiveMinSuccess = 0.5 // これを下回る成功率で β を上げる
+)
+
+// AdaptiveLevels: 自動調整の現在の段階 (0 で設定値のまま)
+type AdaptiveLevels struct {
+	Alpha       int `json:"alpha"`
+	Beta        int `json:"beta"`
+	Evaporation int `json:"evaporation"`
+}
+
+// adaptParams: 直前の Step の結果から、これから行うイテレーションの段階を決める
+func (aco *ACO) adaptParams() {
+	history := aco.Stats.SuccessRate
+	if !aco.Config.Adaptive || len(history) == 0 {
+		return
+	}
+	levels := &aco.Adaptive
+	improved := aco.Stagnation == 0
+	successRate := history[len(history)-1]
+
+	switch {
+	case improved:
+		levels.Alpha -= sign(levels.Alpha)
+		levels.Beta -= sign(levels.Beta)
+		levels.Evaporation -= sign(levels.Evaporation)
+	case aco.Stagnation >= AdaptiveStagnation:
+		levels.Alpha = max(levels.Alpha-1, -AdaptiveMaxLevel)
+		levels.Evaporation = min(levels.Evaporation+1, AdaptiveMaxLevel)
+	}
+	if successRate < AdaptiveMinSuccess {
+		levels.Beta = min(levels.Beta+1, AdaptiveMaxLevel)
+	} else if successRate == 1 && levels.Beta > 0 {
+		levels.Beta--
+	}
+}
+
+// adaptiveScale: 段階 level の倍率 (自動調整が無効なら 1)
+func (aco *ACO) adaptiveScale(level int) float64 {
+	if !aco.Config.Adaptive || level == 0 {
+		return 1
+	}
+	return math.Pow(1+AdaptiveRate, float64(level))
+}
+
+// alpha: 現在のイテレーションで使う α
+func (aco *ACO) alpha() float64 {
+	return aco.Config.Alpha * aco.adaptiveScale(aco.Adaptive.Alpha)
+}
+
+// beta: 現在のイテレーションで使う β
+func (aco *ACO) beta() float64 {
+	return aco.Config.Beta * aco.adaptiveScale(aco.Adaptive.Beta)
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,218 @@
+package solver
+
+import (
+	"math"
+	"sort"
+)
+
+// 隣接リストの操作
+// 無向辺 u-v は Adj[u] と Adj[v] にそれぞれ1つずつ半辺として持つ。
+// 一方通行の辺 u→v は Adj[u] の半辺だけで、OneWay が立つ (フェロモン・距離も向きごとに別)。
+// メモリは O(n + E) で、生成グラフ (平均次数 ~8) なら数千ノードでも軽い。
+// 各 Adj[u] は距離の昇順に保つので、先頭 k 個がそのまま k 近傍の候補リストになる。
+// 半辺 u→v の Dist は辺の重み (混雑モードなら混雑込み) に v の通過コスト (Node.Cost) と、
+// 多目的の重み付き和の項 (RiskWeight * Risk + HopWeight) を足したもの。
+// 経路の距離・ヒューリスティック・厳密解法はすべて Dist を使うので、通過コストも自動的に含まれる。
+// 構築時は全ての行を1つの配列に連続して確保する (アリが隣接リストをたどるときのキャッシュ効率のため)。
+// 各行の容量は確保時の次数ちょうどなので、後から辺を足した行だけが別の配列に移る。
+
+// allocAdjacency: edges を link するための空の隣接リスト (各行は1つの配列上に次数ちょうどの容量で並ぶ)
+func allocAdjacency(nodeCount int, edges []Edge) [][]Neighbor {
+	degree := make([]int, nodeCount)
+	total := 0
+	for _, e := range edges {
+		degree[e.From]++
+		total++
+		if !e.Directed {
+			degree[e.To]++
+			total++
+		}
+	}
+	backing := make([]Neighbor, total)
+	adj := make([][]Neighbor, nodeCount)
+	offset := 0
+	for u, d := range degree {
+		adj[u] = backing[offset : offset : offset+d]
+		offset += d
+	}
+	return adj
+}
+
+// cloneAdjacency: adj の複製 (allocAdjacency と同じく1つの配列に並べる)
+func cloneAdjacency(adj [][]Neighbor) [][]Neighbor {
+	total := 0
+	for _, neighbors := range adj {
+		total += len(neighbors)
+	}
+	backing := make([]Neighbor, 0, total)
+	clone := make([][]Neighbor, len(adj))
+	for u, neighbors := range adj {
+		offset := len(backing)
+		backing = append(backing, neighbors...)
+		clone[u] = backing[offset:len(backing):len(backing)]
+	}
+	return clone
+}
+
+// evaporate: 全ての半辺のフェロモン量に (1 - rate) を掛ける
+// 辺のない組は隣接リストに現れないので O(n + E)。行ごとに連続した半辺を順に書き換える
+func (aco *ACO) evaporate(rate float64) {
+	if rate == 0 {
+		return
+	}
+	keep := 1 - rate
+	for _, row := range aco.Adj {
+		for k := range row {
+			row[k].Pheromone *= keep
+		}
+	}
+}
+
+// neighbor: u から v への半辺 (なければ nil)
+func (aco *ACO) neighbor(u, v int) *Neighbor {
+	for k := range aco.Adj[u] {
+		if aco.Adj[u][k].To == v {
+			return &aco.Adj[u][k]
+		}
+	}
+	return nil
+}
+
+func (aco *ACO) hasEdge(u, v int) bool {
+	return aco.neighbor(u, v) != nil
+}
+
+// distance: 辺 u-v の重みと v の通過コストの和 (接続がなければ +Inf)
+func (aco *ACO) distance(u, v int) float64 {
+	if nb := aco.neighbor(u, v); nb != nil {
+		return nb.Dist
+	}
+	return math.Inf(1)
+}
+
+// pheromone: 辺 u-v のフェロモン量 (接続がなければ 0)
+func (aco *ACO) pheromone(u, v int) float64 {
+	if nb := aco.neighbor(u, v); nb != nil {
+		return nb.Pheromone
+	}
+	return 0
+}
+
+// addPheromone: 辺 u→v に amount を加える (無向辺なら逆向きにも)
+func (aco *ACO) addPheromone(u, v int, amount float64) {
+	nb := aco.neighbor(u, v)
+	if nb == nil {
+		return
+	}
+	nb.Pheromone += amount
+	if nb.OneWay {
+		return
+	}
+	if rev := aco.neighbor(v, u); rev != nil {
+		rev.Pheromone += amount
+	}
+}
+
+// setPheromone: 辺 u→v のフェロモン量を value にする (無向辺なら逆向きも)
+func (aco *ACO) setPheromone(u, v int, value float64) {
+	nb := aco.neighbor(u, v)
+	if nb == nil {
+		return
+	}
+	nb.Pheromone = value
+	if nb.OneWay {
+		return
+	}
+	if rev := aco.neighbor(v, u); rev != nil {
+		rev.Pheromone = value
+	}
+}
+
+// link: 辺 e (Directed なら From→To のみ) を初期フェロモンで追加する (重複チェックは呼び出し側)
+func (aco *ACO) link(e Edge) {
+	u, v := e.From, e.To
+	aco.Adj[u] = insertNeighbor(aco.Adj[u], Neighbor{To: v, Dist: aco.edgeDist(e, 0, v), Pheromone: aco.Config.InitialPheromone, Risk: e.Risk, OneWay: e.Directed})
+	if !e.Directed {
+		aco.Adj[v] = insertNeighbor(aco.Adj[v], Neighbor{To: u, Dist: aco.edgeDist(e, 0, u), Pheromone: aco.Config.InitialPheromone, Risk: e.Risk})
+	}
+}
+
+// insertNeighbor: 距離の昇順を保って nb を挿入する
+func insertNeighbor(neighbors []Neighbor, nb Neighbor) []Neighbor {
+	k := sort.Search(len(neighbors), func(i int) bool { return neighbors[i].Dist > nb.Dist })
+	neighbors = append(neighbors, Neighbor{})
+	copy(neighbors[k+1:], neighbors[k:])
+	neighbors[k] = nb
+	return neighbors
+}
+
+// unlink: 辺 u→v を取り除く (無向辺なら逆向きの半辺も)
+func (aco *ACO) unlink(u, v int) {
+	nb := aco.neighbor(u, v)
+	if nb == nil {
+		return
+	}
+	oneWay := nb.OneWay
+	aco.Adj[u] = removeNeighbor(aco.Adj[u], v)
+	if !oneWay {
+		aco.Adj[v] = removeNeighbor(aco.Adj[v], u)
+	}
+}
+
+func removeNeighbor(neighbors []Neighbor, to int) []Neighbor {
+	for k := range neighbors {
+		if neighbors[k].To == to {
+			return append(neighbors[:k], neighbors[k+1:]...)
+		}
+	}
+	return neighbors
+}
+
+// setDistance: 辺 e の重み・通過コストの変更を半辺に反映する (無向辺なら逆向きも。並び順も付け直す)
+func (aco *ACO) setDistance(e Edge) {
+	if aco.updateDistance(e) {
+		sortNeighbors(aco.Adj[e.From])
+		if !e.Directed {
+			sortNeighbors(aco.Adj[e.To])
+		}
+	}
+}
+
+// updateDistance: setDistance の本体 (並べ替えは呼び出し側)。辺がなければ false
+func (aco *ACO) updateDistance(e Edge) bool {
+	nb := aco.neighbor(e.From, e.To)
+	if nb == nil {
+		return false
+	}
+	nb.Dist = aco.edgeDist(e, nb.Usage, e.To)
+	if nb.OneWay {
+		return true
+	}
+	if rev := aco.neighbor(e.To, e.From); rev != nil {
+		rev.Dist = aco.edgeDist(e, rev.Usage, e.From)
+	}
+	return true
+}
+
+// hasOneWayEdges: 一方通行の辺を含むか
+func (aco *ACO) hasOneWayEdges() bool {
+	for _, e := range aco.Graph.Edges {
+		if e.Directed {
+			return true
+		}
+	}
+	return false
+}
+
+func sortNeighbors(neighbors []Neighbor) {
+	sort.SliceStable(neighbors, func(i, j int) bool { return neighbors[i].Dist < neighbors[j].Dist })
+}
+
+// candidates: current から選択候補にする半辺 (CandidateListSize が 0 なら全て)
+func (aco *ACO) candidates(current int) []Neighbor {
+	neighbors := aco.Adj[current]
+	if k := aco.Config.CandidateListSize; k > 0 && k < len(neighbors) {
+		return neighbors[:k]
+	}
+	return neighbors
+}
@@ -0,0 +1,170 @@
+package solver
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// 多数の出発地・目的地の組 (OD ペア) の経路を、現在のフェロモンからまとめて取り出す (流れの可視化用)
+// "dijkstra" は半辺 u→v のコストを Dist / τ (フェロモンが濃いほど安い) とした最短経路で、
+// 出発地ごとに1回の Dijkstra で全ての目的地への経路を求める。フェロモンが 0 の辺は通らず、
+// 負の重みの辺はコスト 0 として扱う (Dijkstra が成り立つように)。
+// "greedy" は目的地ごとに greedyPath でフェロモンの最も濃い辺をたどり、行き詰まった組は経路なしとする。
+// 親のフェロモン (マルチコロニーではコロニーの平均) を使う。
+
+// AllPairsLimit: 一度に求める組の最大数
+const AllPairsLimit = 10000
+
+// 経路の取り出し方
+const (
+	ExtractDijkstra = "dijkstra" // Dist / τ を重みとする最短経路
+	ExtractGreedy   = "greedy"   // フェロモンの最も濃い辺をたどる (ExtractPheromonePath と同じ)
+)
+
+// pheromoneExtractors: 取り出し方 → source から targets の各ノードへの経路 (targets と同順)
+var pheromoneExtractors = map[string]func(aco *ACO, source int, targets []int) []Route{
+	ExtractDijkstra: (*ACO).pheromoneDijkstra,
+	ExtractGreedy: func(aco *ACO, source int, targets []int) []Route {
+		routes := make([]Route, len(targets))
+		for j, t := range targets {
+			if p := aco.greedyPath(source, t); p.Reached {
+				routes[j] = Route{Path: p.Path, Dist: p.Dist}
+			}
+		}
+		return routes
+	},
+}
+
+// Route: 取り出した1本の経路
+type Route struct {
+	Path []int   `json:"path"` // たどり着けなければ nil
+	Dist float64 `json:"dist"` // 通った半辺の Dist の合計
+}
+
+// RouteMatrix: PheromoneRoutes の結果
+type RouteMatrix struct {
+	Method  string    `json:"method"`
+	Sources []int     `json:"sources"`
+	Targets []int     `json:"targets"`
+	Routes  [][]Route `json:"routes"`  // Routes[i][j] は Sources[i] → Targets[j]
+	Reached int       `json:"reached"` // 経路が見つかった組の数
+}
+
+// ExtractMethodNames: 選べる経路の取り出し方 (ソート済み)
+func ExtractMethodNames() []string {
+	names := make([]string, 0, len(pheromoneExtractors))
+	for name := range pheromoneExtractors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PheromoneRoutes: sources × targets の全ての組について method で経路を取り出す
+// sources・targets が空なら全てのノード
+func (aco *ACO) PheromoneRoutes(sources, targets []int, method string) (RouteMatrix, error) {
+	extract, ok := pheromoneExtractors[method]
+	if !ok {
+		return RouteMatrix{}, fmt.Errorf("%w: unknown extraction method %q (available: %v)", ErrInvalidConfig, method, ExtractMethodNames())
+	}
+	if len(sources) == 0 {
+		sources = aco.allNodes()
+	}
+	if len(targets) == 0 {
+		targets = aco.allNodes()
+	}
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return RouteMatrix{}, err
+	}
+	if pairs := len(sources) * len(targets); pairs > AllPairsLimit {
+		return RouteMatrix{}, fmt.Errorf("%w: %d pairs exceed the limit of %d", ErrInvalidConfig, pairs, AllPairsLimit)
+	}
+	for _, id := range append(append([]int(nil), sources...), targets...) {
+		if err := aco.checkNode(id); err != nil {
+			return RouteMatrix{}, err
+		}
+	}
+
+	result := RouteMatrix{Method: method, Sources: sources, Targets: targets, Routes: make([][]Route, len(sources))}
+	for i, source := range sources {
+		result.Routes[i] = extract(aco, source, targets)
+		for _, route := range result.Routes[i] {
+			if route.Path != nil {
+				result.Reached++
+			}
+		}
+	}
+	return result, nil
+}
+
+// allNodes: 0..n-1
+func (aco *ACO) allNodes() []int {
+	nodes := make([]int, len(aco.Graph.Nodes))
+	for i := range nodes {
+		nodes[i] = i
+	}
+	return nodes
+}
+
+// pheromoneDijkstra: Dist / τ を重みとして source から targets の各ノードへの最短経路を求める
+// 全ての目的地が確定した時点で打ち切る
+func (aco *ACO) pheromoneDijkstra(source int, targets []int) []Route {
+	n := len(aco.Graph.Nodes)
+	isTarget := targetSet(n, targets)
+	remaining := 0
+	for _, t := range isTarget {
+		if t {
+			remaining++
+		}
+	}
+	cost := make([]float64, n)
+	dist := make([]float64, n)
+	prev := make([]int, n)
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	cost[source] = 0
+
+	pq := &priorityQueue{{node: source, priority: 0}}
+	for pq.Len() > 0 && remaining > 0 {
+		item := heap.Pop(pq).(pqItem)
+		u := item.node
+		if item.priority > cost[u] {
+			continue // 古いエントリ
+		}
+		if isTarget[u] {
+			isTarget[u] = false
+			remaining--
+		}
+		for _, nb := range aco.Adj[u] {
+			if nb.Pheromone <= 0 {
+				continue
+			}
+			if alt := cost[u] + max(nb.Dist, 0)/nb.Pheromone; alt < cost[nb.To] {
+				cost[nb.To] = alt
+				dist[nb.To] = dist[u] + nb.Dist
+				prev[nb.To] = u
+				heap.Push(pq, pqItem{node: nb.To, priority: alt})
+			}
+		}
+	}
+
+	routes := make([]Route, len(targets))
+	for j, t := range targets {
+		if math.IsInf(cost[t], 1) {
+			continue
+		}
+		path := []int{}
+		for v := t; v != -1; v = prev[v] {
+			path = append(path, v)
+		}
+		for a, b := 0, len(path)-1; a < b; a, b = a+1, b-1 {
+			path[a], path[b] = path[b], path[a]
+		}
+		routes[j] = Route{Path: path, Dist: dist[t]}
+	}
+	return routes
+}
@@ -0,0 +1,120 @@
+package solver
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// AStarHeuristic: ノードから target までの推定コスト (許容的である必要がある)
+type AStarHeuristic func(aco *ACO, node, target int) float64
+
+// astarHeuristics: 名前で選択できるA*ヒューリスティック
+var astarHeuristics = map[string]AStarHeuristic{
+	"euclidean": euclideanToTarget,
+	"zero":      func(*ACO, int, int) float64 { return 0 }, // Dijkstraと等価
+}
+
+// AStarHeuristicNames: 登録済みヒューリスティック名 (ソート済み)
+func AStarHeuristicNames() []string {
+	names := make([]string, 0, len(astarHeuristics))
+	for name := range astarHeuristics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// euclideanToTarget: target までの座標上の長さ (Config.Metric) を、全辺で成り立つ最小の (重み/長さ) 比で縮めたもの
+// 重みが正規化されていても、任意スケールの読み込みグラフでも下界になる
+func euclideanToTarget(aco *ACO, node, target int) float64 {
+	t := aco.Graph.Nodes[target]
+	n := aco.Graph.Nodes[node]
+	return aco.space().distance(n, t) * aco.weightPerUnitLength()
+}
+
+// weightPerUnitLength: 全辺における 重み/座標上の長さ の最小値
+func (aco *ACO) weightPerUnitLength() float64 {
+	ratio := math.Inf(1)
+	space := aco.space()
+	for _, e := range aco.Graph.Edges {
+		length := space.distance(aco.Graph.Nodes[e.From], aco.Graph.Nodes[e.To])
+		if length == 0 {
+			continue
+		}
+		ratio = math.Min(ratio, e.Weight/length)
+	}
+	if ratio == math.Inf(1) {
+		return 0
+	}
+	return ratio
+}
+
+// SolveAStar: 指定ヒューリスティックでスタートからゴールへの最短経路を求める
+// 経由地があれば区間ごとに解いてつなぐ
+func (aco *ACO) SolveAStar(heuristicName string) (PathResult, error) {
+	heuristic, ok := astarHeuristics[heuristicName]
+	if !ok {
+		return PathResult{}, fmt.Errorf("%w: unknown heuristic %q (available: %v)", ErrInvalidConfig, heuristicName, AStarHeuristicNames())
+	}
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return PathResult{}, err
+	}
+
+	return aco.solveLegs(func(source int, targets []int) (PathResult, error) {
+		return aco.astar(heuristic, source, targets)
+	})
+}
+
+// astar: source から targets のうち最も近いノードへの最短経路
+// 複数の行き先がある場合は各行き先への推定の最小値を使う (許容性を保つ)
+func (aco *ACO) astar(heuristic AStarHeuristic, source int, targets []int) (PathResult, error) {
+	n := len(aco.Graph.Nodes)
+	isTarget := targetSet(n, targets)
+	// ヒューリスティックは展開のたびに呼ぶと重いので事前計算
+	h := make([]float64, n)
+	for i := range h {
+		h[i] = math.Inf(1)
+		for _, t := range targets {
+			h[i] = math.Min(h[i], heuristic(aco, i, t))
+		}
+	}
+
+	dist := make([]float64, n)
+	prev := make([]int, n)
+	closed := make([]bool, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[source] = 0
+
+	expanded, reached := 0, -1
+	pq := &priorityQueue{{node: source, priority: h[source]}}
+	for pq.Len() > 0 {
+		u := heap.Pop(pq).(pqItem).node
+		if closed[u] {
+			continue
+		}
+		closed[u] = true
+		expanded++
+		if isTarget[u] {
+			reached = u
+			break
+		}
+		for _, nb := range aco.Adj[u] {
+			v, w := nb.To, nb.Dist
+			if closed[v] {
+				continue
+			}
+			if alt := dist[u] + w; alt < dist[v] {
+				dist[v] = alt
+				prev[v] = u
+				heap.Push(pq, pqItem{node: v, priority: alt + h[v]})
+			}
+		}
+	}
+
+	return buildPathResult(source, targets, reached, dist, prev, expanded)
+}
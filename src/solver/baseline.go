@@ -0,0 +1,116 @@
+package solver
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 素朴な解法 (ACO がどれだけ改善しているかを示す比較用)
+// どちらもアリと同じ規則 (未訪問ノードのみ、経由地・ゴール・TSP) で経路を作る。
+
+const (
+	BaselineGreedy     = "greedy"      // 常に最も近い未訪問の隣接ノードへ進む
+	BaselineRandomWalk = "random-walk" // 未訪問の隣接ノードから一様に選ぶ
+)
+
+// BaselineConfig: SolveBaseline のパラメータ
+type BaselineConfig struct {
+	// 試行回数 (最も短い成功経路を返す。貪欲法は毎回同じ経路になる)
+	Attempts int `json:"attempts"`
+	// 乱数シード (省略時はインスタンスの Seed)
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+func DefaultBaselineConfig() BaselineConfig {
+	return BaselineConfig{Attempts: 1}
+}
+
+// BaselineResult: 素朴な解法の結果 (失敗時は途中までの経路)
+type BaselineResult struct {
+	Dist      float64 `json:"dist"`
+	Path      []int   `json:"path"`
+	Success   bool    `json:"success"`
+	Successes int     `json:"successes"` // ゴールに着いた試行の数
+}
+
+// baselineSelectors: 解法名ごとの次ノードの選び方 (-1 で行き止まり)
+var baselineSelectors = map[string]func(aco *ACO, rng randIntn) func(current int, visited []bool) int{
+	BaselineGreedy: func(aco *ACO, _ randIntn) func(int, []bool) int {
+		// Adj[u] は距離の昇順なので最初の未訪問ノードが最も近い
+		return func(current int, visited []bool) int {
+			for _, nb := range aco.Adj[current] {
+				if !visited[nb.To] {
+					return nb.To
+				}
+			}
+			return -1
+		}
+	},
+	BaselineRandomWalk: func(aco *ACO, rng randIntn) func(int, []bool) int {
+		var options []int
+		return func(current int, visited []bool) int {
+			options = options[:0]
+			for _, nb := range aco.Adj[current] {
+				if !visited[nb.To] {
+					options = append(options, nb.To)
+				}
+			}
+			if len(options) == 0 {
+				return -1
+			}
+			return options[rng.Intn(len(options))]
+		}
+	},
+}
+
+type randIntn interface{ Intn(n int) int }
+
+// BaselineNames: 利用できる素朴な解法 (ソート済み)
+func BaselineNames() []string {
+	names := make([]string, 0, len(baselineSelectors))
+	for name := range baselineSelectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SolveBaseline: 名前で指定した素朴な解法で経路を作る
+// ACO のフェロモンや乱数には触れないので、同じ問題で ACO と並べて比較できる
+func (aco *ACO) SolveBaseline(name string, cfg BaselineConfig) (BaselineResult, error) {
+	newSelector, ok := baselineSelectors[name]
+	if !ok {
+		return BaselineResult{}, fmt.Errorf("%w: unknown baseline %q (available: %v)", ErrInvalidConfig, name, BaselineNames())
+	}
+	if cfg.Attempts < 1 {
+		return BaselineResult{}, fmt.Errorf("%w: attempts must be >= 1 (got %d)", ErrInvalidConfig, cfg.Attempts)
+	}
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return BaselineResult{}, err
+	}
+	seed := aco.Seed
+	if cfg.Seed != nil {
+		seed = *cfg.Seed
+	}
+	rng, _ := newRand(seed)
+	selectNext := newSelector(aco, rng)
+	aco.prepareOrienteering() // 予算内でゴールへ戻れないノードを候補から外す
+
+	var best BaselineResult
+	for attempt := 0; attempt < cfg.Attempts; attempt++ {
+		walk := aco.constructWith(func(path []int, _ int, visited []bool) int {
+			return selectNext(path[len(path)-1], visited)
+		}, ConstructionSimple, 0, nil)
+		path, success := walk.path, walk.outcome == outcomeSuccess
+		result := BaselineResult{Path: path, Success: success}
+		if success {
+			result.Dist = aco.calculatePathDistance(path)
+			best.Successes++
+		}
+		if attempt == 0 || (success && (!best.Success || result.Dist < best.Dist)) {
+			result.Successes = best.Successes
+			best = result
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,90 @@
+package solver
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// BenchmarkResult: Benchmark の計測結果
+type BenchmarkResult struct {
+	NodeCount        int     `json:"nodeCount"`
+	EdgeCount        int     `json:"edgeCount"`
+	AntCount         int     `json:"antCount"`
+	Workers          int     `json:"workers"`
+	Iterations       int     `json:"iterations"`
+	ElapsedMs        float64 `json:"elapsedMs"`
+	IterationsPerSec float64 `json:"iterationsPerSec"`
+	// アリ1匹あたりの平均経路構築時間 (マイクロ秒)
+	AvgAntConstructionUs float64    `json:"avgAntConstructionUs"`
+	BestDist             float64    `json:"bestDist"`
+	Allocs               AllocStats `json:"allocs"`
+}
+
+// AllocStats: 計測区間中のヒープ確保量 (runtime.MemStats の差分)
+type AllocStats struct {
+	Mallocs           uint64  `json:"mallocs"`
+	TotalBytes        uint64  `json:"totalBytes"`
+	BytesPerIteration float64 `json:"bytesPerIteration"`
+	NumGC             uint32  `json:"numGC"`
+	HeapBytes         uint64  `json:"heapBytes"` // 終了時点の使用中ヒープ
+}
+
+// Benchmark: nodeCount ノードのグラフで budget の間 Step を回し、速度と確保量を計測する
+// hook の通知にかかる時間も計測に含まれる
+func Benchmark(nodeCount int, cfg Config, budget time.Duration, hook ProgressHook) (BenchmarkResult, error) {
+	if nodeCount < 2 {
+		return BenchmarkResult{}, fmt.Errorf("%w: nodeCount must be >= 2 (got %d)", ErrInvalidConfig, nodeCount)
+	}
+	if budget <= 0 {
+		return BenchmarkResult{}, fmt.Errorf("%w: duration must be > 0 (got %s)", ErrInvalidConfig, budget)
+	}
+	if err := cfg.Validate(); err != nil {
+		return BenchmarkResult{}, err
+	}
+
+	aco := NewACO(nodeCount, cfg)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	for time.Since(start) < budget {
+		aco.Step()
+		hook.notify(aco)
+	}
+	elapsed := time.Since(start)
+
+	runtime.ReadMemStats(&after)
+
+	result := BenchmarkResult{
+		NodeCount:        len(aco.Graph.Nodes),
+		EdgeCount:        len(aco.Graph.Edges),
+		AntCount:         cfg.AntCount,
+		Workers:          cfg.Workers,
+		Iterations:       aco.Iteration,
+		ElapsedMs:        float64(elapsed) / float64(time.Millisecond),
+		IterationsPerSec: float64(aco.Iteration) / elapsed.Seconds(),
+		BestDist:         aco.BestDist,
+		Allocs: AllocStats{
+			Mallocs:    after.Mallocs - before.Mallocs,
+			TotalBytes: after.TotalAlloc - before.TotalAlloc,
+			NumGC:      after.NumGC - before.NumGC,
+			HeapBytes:  after.HeapAlloc,
+		},
+	}
+	if aco.Iteration > 0 {
+		constructTime, colonies := aco.constructTime, 1
+		if len(aco.Colonies) > 0 {
+			colonies = len(aco.Colonies)
+			for _, colony := range aco.Colonies {
+				constructTime += colony.constructTime
+			}
+		}
+		ants := float64(aco.Iteration * cfg.AntCount * colonies)
+		result.AvgAntConstructionUs = float64(constructTime) / float64(time.Microsecond) / ants
+		result.Allocs.BytesPerIteration = float64(result.Allocs.TotalBytes) / float64(aco.Iteration)
+	}
+	return result, nil
+}
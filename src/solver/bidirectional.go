@@ -0,0 +1,172 @@
+package solver
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// 双方向探索 (スタートからの前向き探索とゴールからの後ろ向き探索を交互に進め、出会ったところでつなぐ)
+// 後ろ向き探索は辺を逆向きにたどる (一方通行の辺も正しく扱う)。複数ゴールは全ゴールを同時に始点とする。
+// A* のヒューリスティックは前向き・後ろ向きの推定の平均 (average potential) にして、両方向で矛盾しないようにする。
+
+// BidirectionalResult: 双方向探索の結果
+type BidirectionalResult struct {
+	PathResult
+	Meetings []int `json:"meetings"` // 区間ごとの前向き・後ろ向き探索が出会ったノード
+}
+
+// SolveBidirectional: 指定ヒューリスティックの双方向探索でスタートからゴールへの最短経路を求める
+// "zero" なら双方向 Dijkstra。経由地があれば区間ごとに解いてつなぐ
+func (aco *ACO) SolveBidirectional(heuristicName string) (BidirectionalResult, error) {
+	heuristic, ok := astarHeuristics[heuristicName]
+	if !ok {
+		return BidirectionalResult{}, fmt.Errorf("%w: unknown heuristic %q (available: %v)", ErrInvalidConfig, heuristicName, AStarHeuristicNames())
+	}
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return BidirectionalResult{}, err
+	}
+
+	reverseAdj := aco.reverseAdjacency()
+	var meetings []int
+	path, err := aco.solveLegs(func(source int, targets []int) (PathResult, error) {
+		leg, meeting, err := aco.bidirectional(heuristic, reverseAdj, source, targets)
+		meetings = append(meetings, meeting)
+		return leg, err
+	})
+	if err != nil {
+		return BidirectionalResult{}, err
+	}
+	return BidirectionalResult{PathResult: path, Meetings: meetings}, nil
+}
+
+// reverseAdjacency: 辺を逆向きにした隣接リスト (v から見て v へ入ってくる辺)
+func (aco *ACO) reverseAdjacency() [][]Neighbor {
+	reverseAdj := make([][]Neighbor, len(aco.Adj))
+	for u, neighbors := range aco.Adj {
+		for _, nb := range neighbors {
+			reverseAdj[nb.To] = append(reverseAdj[nb.To], Neighbor{To: u, Dist: nb.Dist, OneWay: nb.OneWay})
+		}
+	}
+	return reverseAdj
+}
+
+// bidirectional: source から targets のうち最も近いノードへの最短経路と、両方向の探索が出会ったノード
+func (aco *ACO) bidirectional(heuristic AStarHeuristic, reverseAdj [][]Neighbor, source int, targets []int) (PathResult, int, error) {
+	n := len(aco.Graph.Nodes)
+	// 前向きのポテンシャル p(v) = (targets までの推定 - source からの推定) / 2、後ろ向きは -p(v)
+	potential := make([]float64, n)
+	for i := range potential {
+		toTarget := math.Inf(1)
+		for _, t := range targets {
+			toTarget = math.Min(toTarget, heuristic(aco, i, t))
+		}
+		potential[i] = (toTarget - heuristic(aco, i, source)) / 2
+	}
+
+	forward := newSearchSide(n, aco.Adj, potential, 1)
+	backward := newSearchSide(n, reverseAdj, potential, -1)
+	forward.start(source)
+	for _, t := range targets {
+		backward.start(t)
+	}
+
+	best, meeting := math.Inf(1), -1
+	if targetSet(n, targets)[source] {
+		best, meeting = 0, source
+	}
+	expanded := 0
+	for forward.pq.Len() > 0 && backward.pq.Len() > 0 {
+		// 両方向の最小キーの和が見つかった経路長以上なら、それより短い経路は残っていない
+		if forward.top()+backward.top() >= best {
+			break
+		}
+		side, other := forward, backward
+		if backward.top() < forward.top() {
+			side, other = backward, forward
+		}
+		for _, v := range side.expand() {
+			if alt := side.dist[v] + other.dist[v]; alt < best {
+				best, meeting = alt, v
+			}
+		}
+		expanded++
+	}
+	if meeting == -1 {
+		return PathResult{}, -1, fmt.Errorf("%w: no path from %d to %v", ErrUnreachable, source, targets)
+	}
+
+	path := []int{}
+	for v := meeting; v != -1; v = forward.prev[v] {
+		path = append(path, v)
+	}
+	reverse(path)
+	for v := backward.prev[meeting]; v != -1; v = backward.prev[v] {
+		path = append(path, v)
+	}
+	return PathResult{Dist: best, Path: path, Expanded: expanded}, meeting, nil
+}
+
+// searchSide: 双方向探索の片側の状態
+type searchSide struct {
+	adj       [][]Neighbor
+	potential []float64
+	sign      float64 // 前向きは 1、後ろ向きは -1 (ポテンシャルの符号)
+	dist      []float64
+	prev      []int // 前向きは1つ前、後ろ向きは1つ後のノード
+	closed    []bool
+	pq        *priorityQueue
+}
+
+func newSearchSide(n int, adj [][]Neighbor, potential []float64, sign float64) *searchSide {
+	side := &searchSide{
+		adj:       adj,
+		potential: potential,
+		sign:      sign,
+		dist:      make([]float64, n),
+		prev:      make([]int, n),
+		closed:    make([]bool, n),
+		pq:        &priorityQueue{},
+	}
+	for i := range side.dist {
+		side.dist[i] = math.Inf(1)
+		side.prev[i] = -1
+	}
+	return side
+}
+
+func (s *searchSide) start(v int) {
+	s.dist[v] = 0
+	heap.Push(s.pq, pqItem{node: v, priority: s.sign * s.potential[v]})
+}
+
+// top: 未確定ノードの最小キー (確定済みの古いエントリは捨てる)
+func (s *searchSide) top() float64 {
+	for s.pq.Len() > 0 && s.closed[(*s.pq)[0].node] {
+		heap.Pop(s.pq)
+	}
+	if s.pq.Len() == 0 {
+		return math.Inf(1)
+	}
+	return (*s.pq)[0].priority
+}
+
+// expand: キー最小のノードを確定し、距離が縮んだ隣接ノードを返す
+func (s *searchSide) expand() []int {
+	u := heap.Pop(s.pq).(pqItem).node
+	s.closed[u] = true
+	var relaxed []int
+	for _, nb := range s.adj[u] {
+		v := nb.To
+		if s.closed[v] {
+			continue
+		}
+		if alt := s.dist[u] + nb.Dist; alt < s.dist[v] {
+			s.dist[v] = alt
+			s.prev[v] = u
+			heap.Push(s.pq, pqItem{node: v, priority: alt + s.sign*s.potential[v]})
+			relaxed = append(relaxed, v)
+		}
+	}
+	return relaxed
+}
@@ -0,0 +1,43 @@
+package solver
+
+// 対応している選択肢の一覧 (ビルドごとの違いを JS 側で確かめられるようにする)
+
+// Capabilities: Config などで選べる名前の一覧 (それぞれソート済み)
+type Capabilities struct {
+	Modes                []string `json:"modes"`
+	Variants             []string `json:"variants"`
+	Topologies           []string `json:"topologies"`
+	Metrics              []string `json:"metrics"`
+	Normalizations       []string `json:"normalizations"`
+	Constructions        []string `json:"constructions"`
+	EvaporationSchedules []string `json:"evaporationSchedules"`
+	ExchangeModes        []string `json:"exchangeModes"`
+	RNGs                 []string `json:"rngs"`
+	Heuristics           []string `json:"heuristics"` // RegisterHeuristic で登録したものを含む
+	AStarHeuristics      []string `json:"astarHeuristics"`
+	Baselines            []string `json:"baselines"`
+	ExtractMethods       []string `json:"extractMethods"`
+	CompareSolvers       []string `json:"compareSolvers"`
+	ExportFormats        []string `json:"exportFormats"`
+}
+
+// SupportedCapabilities: このビルドで選べる名前の一覧
+func SupportedCapabilities() Capabilities {
+	return Capabilities{
+		Modes:                []string{ModeOrienteering, ModeRoute, ModeTSP},
+		Variants:             []string{VariantAS, VariantRank},
+		Topologies:           TopologyNames(),
+		Metrics:              []string{MetricEuclidean, MetricHaversine, MetricManhattan},
+		Normalizations:       []string{NormalizeExtent, NormalizeNone, NormalizeSpace},
+		Constructions:        []string{ConstructionBacktrack, ConstructionLoopErasure, ConstructionSimple},
+		EvaporationSchedules: []string{ScheduleConstant, ScheduleCosine, ScheduleExponential, ScheduleLinear},
+		ExchangeModes:        []string{ExchangeBest, ExchangePheromone},
+		RNGs:                 RNGNames(),
+		Heuristics:           HeuristicNames(),
+		AStarHeuristics:      AStarHeuristicNames(),
+		Baselines:            BaselineNames(),
+		ExtractMethods:       ExtractMethodNames(),
+		CompareSolvers:       CompareSolverNames(),
+		ExportFormats:        ExportFormats(),
+	}
+}
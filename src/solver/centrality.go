@@ -0,0 +1,133 @@
+package solver
+
+import (
+	"container/heap"
+	"math"
+)
+
+// 辺の媒介中心性 (Brandes 法) と、フェロモンとの相関
+// 最短経路は探索と同じ重み (Adj の Dist、通過コスト込み) と辺の向きで測り、
+// 全てのノードの組 (s, t) について、s から t への最短経路のうちその辺を通るものの割合を足し合わせる。
+// 無向辺は両向きの寄与の合計。ノードが MetricsExactLimit を超えるときは MetricsSamples 個の
+// 始点からの寄与を n/標本数 倍して推定する。
+
+// centralityTolerance: 同じ長さの最短経路とみなす相対誤差
+const centralityTolerance = 1e-9
+
+// Centrality: EdgeCentrality の結果
+type Centrality struct {
+	// 辺ごとの媒介中心性 (Graph.Edges と同じ順)
+	Edges []float64 `json:"edges"`
+	Max   float64   `json:"max"`
+	Exact bool      `json:"exact"`
+	// 辺ごとの媒介中心性とフェロモン量のピアソン相関係数 (どちらかが一定なら 0)
+	PheromoneCorrelation float64 `json:"pheromoneCorrelation"`
+}
+
+// EdgeCentrality: 現在のグラフの辺の媒介中心性を求める (負の重みがあれば最短経路が定まらないのでエラー)
+func (aco *ACO) EdgeCentrality() (Centrality, error) {
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return Centrality{}, err
+	}
+	n := len(aco.Graph.Nodes)
+	edgeIndex := make(map[[2]int]int, 2*len(aco.Graph.Edges))
+	for i, e := range aco.Graph.Edges {
+		edgeIndex[[2]int{e.From, e.To}] = i
+		if !e.Directed {
+			edgeIndex[[2]int{e.To, e.From}] = i
+		}
+	}
+
+	result := Centrality{Edges: make([]float64, len(aco.Graph.Edges)), Exact: n <= MetricsExactLimit}
+	sources := make([]int, 0, n)
+	if result.Exact {
+		for v := 0; v < n; v++ {
+			sources = append(sources, v)
+		}
+	} else {
+		for i := 0; i < MetricsSamples; i++ {
+			sources = append(sources, i*n/MetricsSamples)
+		}
+	}
+
+	dist := make([]float64, n)
+	sigma := make([]float64, n) // 始点からの最短経路の本数
+	delta := make([]float64, n) // 始点から先の依存度
+	preds := make([][]int, n)
+	for _, s := range sources {
+		for v := 0; v < n; v++ {
+			dist[v], sigma[v], delta[v] = math.Inf(1), 0, 0
+			preds[v] = preds[v][:0]
+		}
+		dist[s], sigma[s] = 0, 1
+
+		// 確定した順 (距離の昇順) に並べ、逆順にたどって依存度を集める
+		var order []int
+		pq := &priorityQueue{{node: s, priority: 0}}
+		for pq.Len() > 0 {
+			item := heap.Pop(pq).(pqItem)
+			u := item.node
+			if item.priority > dist[u] {
+				continue // 古いエントリ
+			}
+			order = append(order, u)
+			for _, nb := range aco.Adj[u] {
+				v, alt := nb.To, dist[u]+nb.Dist
+				switch {
+				case math.Abs(alt-dist[v]) <= centralityTolerance*math.Max(1, alt):
+					sigma[v] += sigma[u]
+					preds[v] = append(preds[v], u)
+				case alt < dist[v]:
+					dist[v], sigma[v] = alt, sigma[u]
+					preds[v] = append(preds[v][:0], u)
+					heap.Push(pq, pqItem{node: v, priority: alt})
+				}
+			}
+		}
+		for i := len(order) - 1; i >= 0; i-- {
+			v := order[i]
+			for _, u := range preds[v] {
+				c := sigma[u] / sigma[v] * (1 + delta[v])
+				result.Edges[edgeIndex[[2]int{u, v}]] += c
+				delta[u] += c
+			}
+		}
+	}
+
+	scale := float64(n) / float64(len(sources))
+	pheromones := make([]float64, len(aco.Graph.Edges))
+	for i, e := range aco.Graph.Edges {
+		if !result.Exact {
+			result.Edges[i] *= scale
+		}
+		result.Max = math.Max(result.Max, result.Edges[i])
+		pheromones[i] = aco.pheromone(e.From, e.To)
+	}
+	result.PheromoneCorrelation = correlation(result.Edges, pheromones)
+	return result, nil
+}
+
+// correlation: xs と ys のピアソン相関係数 (どちらかの分散が 0 なら 0)
+func correlation(xs, ys []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
@@ -0,0 +1,187 @@
+package solver
+
+import "math"
+
+// マルチコロニー (Config.Colonies > 1)
+// 同じグラフ上でフェロモンを別々に持つ k 個のコロニーを走らせ、
+// ExchangeInterval イテレーションごとに情報を交換する。
+// 親インスタンス自身はアリを走らせず、各コロニーの結果をまとめる
+// (BestDist/BestPath は全体のベスト、Adj のフェロモンは全コロニーの平均)。
+
+// コロニー間の交換方法 (Config.ExchangeMode)
+const (
+	ExchangeBest      = "best"      // リング状に隣のコロニーへベスト経路を渡す
+	ExchangePheromone = "pheromone" // 全コロニーのフェロモンを平均する
+)
+
+// ColonyBest: コロニーごとのベスト
+type ColonyBest struct {
+	Colony   int     `json:"colony"`
+	BestDist float64 `json:"bestDist"`
+	BestPath []int   `json:"bestPath"`
+}
+
+// spawnColonies: 現在のグラフを複製して Config.Colonies 個のコロニーを作る
+func (aco *ACO) spawnColonies() {
+	aco.Colonies = make([]*ACO, aco.Config.Colonies)
+	for i := range aco.Colonies {
+		aco.Colonies[i] = aco.cloneColony(aco.Rand.Int63())
+	}
+}
+
+// cloneColony: グラフと隣接リストを複製した、独自の乱数源を持つコロニー
+func (aco *ACO) cloneColony(seed int64) *ACO {
+	cfg := aco.Config
+	cfg.Colonies = 0
+	cfg.TopK = 0   // 上位K経路は親がまとめて持つ
+	cfg.Pareto = 0 // パレート解も同じ
+	cfg.Record = 0 // 記録も親だけが取る
+
+	graph := GraphData{
+		Nodes:     append([]Node(nil), aco.Graph.Nodes...),
+		Edges:     append([]Edge(nil), aco.Graph.Edges...),
+		Mode:      aco.Graph.Mode,
+		Is3D:      aco.Graph.Is3D,
+		Torus:     aco.Graph.Torus,
+		Obstacles: aco.Graph.Obstacles,
+	}
+	randSource, src := newSearchRand(cfg, seed)
+	return &ACO{
+		Config:    cfg,
+		Graph:     graph,
+		Adj:       cloneAdjacency(aco.Adj),
+		BestDist:  math.MaxFloat64,
+		Rand:      randSource,
+		randState: src,
+		Seed:      seed,
+		StartNode: aco.StartNode,
+		GoalNode:  aco.GoalNode,
+	}
+}
+
+// forEachColony: 親に適用した編集を各コロニーにも適用し、グラフを揃えておく
+func (aco *ACO) forEachColony(edit func(colony *ACO) error) error {
+	for _, colony := range aco.Colonies {
+		if err := edit(colony); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stepColonies: 全コロニーを1イテレーション進め、ベストと統計をまとめる
+// 返すアリの結果には所属コロニーの番号が付く
+func (aco *ACO) stepColonies() []AntResult {
+	antResults := aco.antResults[:0] // Step と同じく作業領域を使い回す
+	improved := false
+
+	for i, colony := range aco.Colonies {
+		for _, result := range colony.Step() {
+			result.Colony = i
+			antResults = append(antResults, result)
+			if result.Success {
+				aco.recordTopPath(result.Path, result.Dist)
+				aco.recordPareto(result.Path, result.Dist)
+			}
+		}
+		if r := colony.Stats.Restarts; len(r) > 0 && r[len(r)-1].Iteration == colony.Iteration {
+			restart := r[len(r)-1]
+			restart.Colony = i
+			aco.Stats.Restarts = append(aco.Stats.Restarts, restart)
+		}
+		if colony.BestPath != nil && aco.improvesBest(colony.BestPrize, colony.BestDist) {
+			aco.BestDist = colony.BestDist
+			aco.BestPrize = colony.BestPrize
+			aco.BestPath = append([]int(nil), colony.BestPath...)
+			improved = true
+		}
+	}
+
+	if m := aco.Config.ExchangeInterval; m > 0 && aco.Iteration%m == 0 {
+		switch aco.Config.ExchangeMode {
+		case ExchangePheromone:
+			aco.exchangePheromones()
+		default:
+			aco.exchangeBest()
+		}
+	}
+	aco.averagePheromones()
+	aco.countUsage(antResults) // 全コロニーのアリの通過数 (経路多様性・累計用)
+
+	if improved {
+		aco.Stagnation = 0
+		aco.recordImprovement()
+	} else {
+		aco.Stagnation++
+	}
+	aco.recordStats(antResults)
+
+	aco.antResults = antResults
+	return antResults
+}
+
+// exchangeBest: コロニー i は i-1 のベストを受け取り、自分より良ければ採用して強化する
+func (aco *ACO) exchangeBest() {
+	k := len(aco.Colonies)
+	received := make([]ColonyBest, k)
+	for i := range aco.Colonies {
+		from := aco.Colonies[(i+k-1)%k]
+		received[i] = ColonyBest{BestDist: from.BestDist, BestPath: from.BestPath}
+	}
+
+	for i, colony := range aco.Colonies {
+		best := received[i]
+		if best.BestPath == nil {
+			continue
+		}
+		prize := 0.0
+		if colony.orienteering() {
+			prize = colony.pathPrize(best.BestPath)
+		}
+		if !colony.improvesBest(prize, best.BestDist) {
+			continue
+		}
+		colony.BestDist = best.BestDist
+		colony.BestPrize = prize
+		colony.BestPath = append([]int(nil), best.BestPath...)
+		colony.depositAlong(colony.BestPath, colony.depositAmount(colony.BestPrize, colony.BestDist))
+	}
+}
+
+// exchangePheromones: 全コロニーのフェロモンを辺ごとに平均して揃える
+func (aco *ACO) exchangePheromones() {
+	aco.averagePheromones()
+	for _, colony := range aco.Colonies {
+		for u := range colony.Adj {
+			for k := range colony.Adj[u] {
+				colony.Adj[u][k].Pheromone = aco.pheromone(u, colony.Adj[u][k].To)
+			}
+		}
+	}
+}
+
+// averagePheromones: 親の Adj のフェロモンを全コロニーの平均にする (表示用)
+// 混雑モードでは各コロニーが自分の通過数で Adj[u] を並べ直すので、半辺は位置ではなく行き先で対応させる
+func (aco *ACO) averagePheromones() {
+	for u := range aco.Adj {
+		for k := range aco.Adj[u] {
+			total := 0.0
+			for _, colony := range aco.Colonies {
+				total += colony.pheromone(u, aco.Adj[u][k].To)
+			}
+			aco.Adj[u][k].Pheromone = total / float64(len(aco.Colonies))
+		}
+	}
+}
+
+// ColonyBests: コロニーごとのベスト (単一コロニーなら nil)
+func (aco *ACO) ColonyBests() []ColonyBest {
+	if len(aco.Colonies) == 0 {
+		return nil
+	}
+	bests := make([]ColonyBest, len(aco.Colonies))
+	for i, colony := range aco.Colonies {
+		bests[i] = ColonyBest{Colony: i, BestDist: colony.BestDist, BestPath: colony.BestPath}
+	}
+	return bests
+}
@@ -0,0 +1,41 @@
+package solver
+
+import "testing"
+
+// 混雑モードではコロニーごとに Adj[u] の並びが変わるので、フェロモンの交換は行き先で対応させる
+func TestExchangePheromonesWithCongestion(t *testing.T) {
+	seed := int64(7)
+	cfg := DefaultConfig()
+	cfg.Seed = &seed
+	cfg.Colonies = 3
+	cfg.Congestion = 1
+	cfg.ExchangeMode = ExchangePheromone
+	cfg.ExchangeInterval = 2
+	aco := NewACO(40, cfg)
+
+	reordered := 0
+	for step := 0; step < 20; step++ {
+		aco.Step()
+		if aco.Iteration%cfg.ExchangeInterval != 0 {
+			continue
+		}
+		for c, colony := range aco.Colonies {
+			for u := range aco.Adj {
+				if len(colony.Adj[u]) != len(aco.Adj[u]) {
+					t.Fatalf("step %d colony %d: node %d has %d neighbors, parent has %d", step, c, u, len(colony.Adj[u]), len(aco.Adj[u]))
+				}
+				for k, nb := range colony.Adj[u] {
+					if nb.To != aco.Adj[u][k].To {
+						reordered++
+					}
+					if want := aco.pheromone(u, nb.To); nb.Pheromone != want {
+						t.Fatalf("step %d colony %d: pheromone %d->%d = %v, parent has %v", step, c, u, nb.To, nb.Pheromone, want)
+					}
+				}
+			}
+		}
+	}
+	if reordered == 0 {
+		t.Fatal("congestion never reordered a colony's neighbors; the test does not cover the mismatch")
+	}
+}
@@ -0,0 +1,200 @@
+package solver
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 解法の比較 (同じグラフ・スタート・ゴールで登録済みの解法を並べて実行し、表にできる形で返す)
+// 各解法はインスタンスの複製 (フェロモンと統計は初期状態) で動かすので、互いにも元のインスタンスにも影響しない。
+
+// Compare で ACO を回す既定のイテレーション数 (GA の世代数も同じ)
+const CompareIterations = 100
+
+// CompareConfig: Compare のパラメータ
+type CompareConfig struct {
+	// ACO のイテレーション数と GA の世代数
+	Iterations int `json:"iterations"`
+	// 実行する解法 (省略時は CompareSolverNames の全て)
+	Solvers  []string       `json:"solvers,omitempty"`
+	SA       SAConfig       `json:"sa"`
+	GA       GAConfig       `json:"ga"`
+	Baseline BaselineConfig `json:"baseline"`
+}
+
+func DefaultCompareConfig() CompareConfig {
+	return CompareConfig{
+		Iterations: CompareIterations,
+		SA:         DefaultSAConfig(),
+		GA:         DefaultGAConfig(),
+		Baseline:   DefaultBaselineConfig(),
+	}
+}
+
+// SolverResult: 1つの解法の結果 (表の1行)
+type SolverResult struct {
+	Solver string   `json:"solver"`
+	Dist   *float64 `json:"dist"` // 失敗時は nil
+	Path   []int    `json:"path,omitempty"`
+	// 探索で確定(展開)したノード数 (最短経路系)
+	Expanded int `json:"expanded,omitempty"`
+	// 反復回数 (ACO のイテレーション、焼きなましの反復、GA の世代、素朴な解法の試行)
+	Iterations int     `json:"iterations,omitempty"`
+	ElapsedMs  float64 `json:"elapsedMs"`
+	// 比較した中で最も短い距離に対する超過率 (dist - 最短) / 最短
+	Gap   *float64 `json:"gap,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// Comparison: Compare の結果 (Results は指定順)
+type Comparison struct {
+	NodeCount int            `json:"nodeCount"`
+	EdgeCount int            `json:"edgeCount"`
+	Seed      int64          `json:"seed"`
+	Results   []SolverResult `json:"results"`
+}
+
+// compareSolvers: 名前で選択できる比較対象の解法 (result に距離・経路・仕事量を埋める)
+var compareSolvers = map[string]func(aco *ACO, cfg CompareConfig, result *SolverResult) error{
+	"aco": func(aco *ACO, cfg CompareConfig, result *SolverResult) error {
+		if _, err := aco.Run(cfg.Iterations); err != nil {
+			return err
+		}
+		result.Iterations = aco.Iteration
+		if aco.BestPath == nil {
+			return fmt.Errorf("%w: no ant reached the goal in %d iterations", ErrUnreachable, cfg.Iterations)
+		}
+		result.Dist, result.Path = &aco.BestDist, aco.BestPath
+		return nil
+	},
+	"dijkstra": func(aco *ACO, _ CompareConfig, result *SolverResult) error {
+		return pathRow(result)(aco.SolveDijkstra())
+	},
+	"astar": func(aco *ACO, _ CompareConfig, result *SolverResult) error {
+		return pathRow(result)(aco.SolveAStar("euclidean"))
+	},
+	"bellman-ford": func(aco *ACO, _ CompareConfig, result *SolverResult) error {
+		return pathRow(result)(aco.SolveBellmanFord())
+	},
+	"bidirectional": func(aco *ACO, _ CompareConfig, result *SolverResult) error {
+		path, err := aco.SolveBidirectional("euclidean")
+		return pathRow(result)(path.PathResult, err)
+	},
+	"sa": func(aco *ACO, cfg CompareConfig, result *SolverResult) error {
+		sa, err := aco.SolveSA(cfg.SA)
+		if err != nil {
+			return err
+		}
+		result.Dist, result.Path, result.Iterations = &sa.Dist, sa.Path, sa.Iterations
+		return nil
+	},
+	"ga": func(aco *ACO, cfg CompareConfig, result *SolverResult) error {
+		var ga GAResult
+		for gaConfig := &cfg.GA; ga.Generation < cfg.Iterations; gaConfig = nil {
+			var err error
+			if ga, err = aco.StepGA(gaConfig); err != nil {
+				return err
+			}
+		}
+		result.Dist, result.Path, result.Iterations = &ga.BestDist, ga.BestPath, ga.Generation
+		return nil
+	},
+	BaselineGreedy:     baselineRow(BaselineGreedy),
+	BaselineRandomWalk: baselineRow(BaselineRandomWalk),
+}
+
+// pathRow: 最短経路系の結果を result に写す
+func pathRow(result *SolverResult) func(path PathResult, err error) error {
+	return func(path PathResult, err error) error {
+		if err != nil {
+			return err
+		}
+		result.Dist, result.Path, result.Expanded = &path.Dist, path.Path, path.Expanded
+		return nil
+	}
+}
+
+// baselineRow: 素朴な解法を比較対象として包む
+func baselineRow(name string) func(aco *ACO, cfg CompareConfig, result *SolverResult) error {
+	return func(aco *ACO, cfg CompareConfig, result *SolverResult) error {
+		baseline, err := aco.SolveBaseline(name, cfg.Baseline)
+		if err != nil {
+			return err
+		}
+		result.Iterations = cfg.Baseline.Attempts
+		if !baseline.Success {
+			return fmt.Errorf("%w: no attempt reached the goal", ErrUnreachable)
+		}
+		result.Dist, result.Path = &baseline.Dist, baseline.Path
+		return nil
+	}
+}
+
+// CompareSolverNames: 比較できる解法名 (ソート済み)
+func CompareSolverNames() []string {
+	names := make([]string, 0, len(compareSolvers))
+	for name := range compareSolvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compare: 現在のグラフ・スタート・経由地・ゴールで各解法を実行し、距離・仕事量・所要時間を並べる
+// 解法ごとの失敗 (到達不能、TSP 非対応など) は Error に記録して残りを続ける
+func (aco *ACO) Compare(cfg CompareConfig) (Comparison, error) {
+	if cfg.Iterations < 1 {
+		return Comparison{}, fmt.Errorf("%w: iterations must be >= 1 (got %d)", ErrInvalidConfig, cfg.Iterations)
+	}
+	names := cfg.Solvers
+	if len(names) == 0 {
+		names = CompareSolverNames()
+	}
+	for _, name := range names {
+		if _, ok := compareSolvers[name]; !ok {
+			return Comparison{}, fmt.Errorf("%w: unknown solver %q (available: %v)", ErrInvalidConfig, name, CompareSolverNames())
+		}
+	}
+	snapshot, err := aco.Snapshot()
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	comparison := Comparison{
+		NodeCount: len(aco.Graph.Nodes),
+		EdgeCount: len(aco.Graph.Edges),
+		Seed:      aco.Seed,
+		Results:   make([]SolverResult, 0, len(names)),
+	}
+	shortest := -1.0
+	for _, name := range names {
+		result := SolverResult{Solver: name}
+		instance, err := RestoreSnapshot(snapshot)
+		if err != nil {
+			return Comparison{}, err
+		}
+		instance.Reset(true)
+
+		start := time.Now()
+		err = compareSolvers[name](instance, cfg, &result)
+		result.ElapsedMs = float64(time.Since(start)) / float64(time.Millisecond)
+		if err != nil {
+			result.Dist, result.Path = nil, nil
+			result.Error = err.Error()
+		} else if shortest < 0 || *result.Dist < shortest {
+			shortest = *result.Dist
+		}
+		comparison.Results = append(comparison.Results, result)
+	}
+
+	if shortest > 0 {
+		for i := range comparison.Results {
+			if dist := comparison.Results[i].Dist; dist != nil {
+				gap := (*dist - shortest) / shortest
+				comparison.Results[i].Gap = &gap
+			}
+		}
+	}
+	return comparison, nil
+}
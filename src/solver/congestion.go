@@ -0,0 +1,86 @@
+package solver
+
+// 混雑モデル (Config.Congestion > 0)
+// Step ごとに各辺を通ったアリの数を数え、次のイテレーションではその数に応じて辺の重みを増やす。
+// 混んだ道を避けるアリが別の道に分散する様子 (交通の負荷分散) を見せるためのもの。
+// ベスト経路・上位経路の距離も混雑込みの重みで測り直すので、時間とともに変わりうる。
+
+// edgeDist: 辺 e を to へ向かって通るときの距離 (混雑込みの重み + 多目的の項 + to の通過コスト)
+func (aco *ACO) edgeDist(e Edge, usage int, to int) float64 {
+	weight := e.Weight
+	if aco.Config.Congestion > 0 && usage > 0 {
+		capacity := e.Capacity
+		if capacity == 0 {
+			capacity = 1
+		}
+		weight *= 1 + aco.Config.Congestion*float64(usage)/capacity
+	}
+	return weight + aco.Config.RiskWeight*e.Risk + aco.Config.HopWeight + aco.Graph.Nodes[to].Cost
+}
+
+// countUsage: このイテレーションで各辺を通ったアリの数を数え直し、累計 (Traffic) に足す
+// (ゴールできなかったアリも含む)
+func (aco *ACO) countUsage(antResults []AntResult) {
+	for u := range aco.Adj {
+		for k := range aco.Adj[u] {
+			aco.Adj[u][k].Usage = 0
+		}
+	}
+	for _, result := range antResults {
+		path := result.Path
+		for i := 0; i < len(path)-1; i++ {
+			aco.addUsage(path[i], path[i+1])
+		}
+		if aco.Config.Mode == ModeTSP && result.Success && len(path) > 1 {
+			aco.addUsage(path[len(path)-1], path[0])
+		}
+	}
+	for _, row := range aco.Adj {
+		for k := range row {
+			row[k].Traffic += row[k].Usage
+		}
+	}
+}
+
+// addUsage: 辺 u→v の通過数を1増やす (無向辺なら逆向きの半辺にも)
+func (aco *ACO) addUsage(u, v int) {
+	nb := aco.neighbor(u, v)
+	if nb == nil {
+		return
+	}
+	nb.Usage++
+	if nb.OneWay {
+		return
+	}
+	if rev := aco.neighbor(v, u); rev != nil {
+		rev.Usage++
+	}
+}
+
+// applyCongestion: 通過数に応じて全ての半辺の距離を更新し、ベスト経路と上位経路を測り直す
+func (aco *ACO) applyCongestion() {
+	if aco.Config.Congestion == 0 {
+		return
+	}
+	aco.rescoreDistances()
+}
+
+// rescoreDistances: 全ての半辺の距離を計算し直し、ベスト経路・上位経路・パレート解を測り直す
+func (aco *ACO) rescoreDistances() {
+	aco.refreshDistances()
+	if aco.BestPath != nil {
+		aco.BestDist = aco.calculatePathDistance(aco.BestPath)
+	}
+	aco.rescoreTopPaths()
+	aco.rescorePareto()
+}
+
+// refreshDistances: 全ての辺の距離を現在の通過数で計算し直し、隣接リストを並べ直す
+func (aco *ACO) refreshDistances() {
+	for _, e := range aco.Graph.Edges {
+		aco.updateDistance(e)
+	}
+	for u := range aco.Adj {
+		sortNeighbors(aco.Adj[u])
+	}
+}
@@ -0,0 +1,213 @@
+package solver
+
+import (
+	"math"
+	"sort"
+)
+
+// 連結性の診断 (読み込んだグラフで解のない探索を黙って回さないための報告) と修復
+// 連結成分は辺の向きを無視して数え、到達可能性は一方通行の向きに従って調べる。
+// Config.ConnectComponents なら生成・読み込みの後に、成分どうしを最も近いノードの組を結ぶ辺 (Edge.Bridge) でつなぐ。
+
+// GraphReport: グラフの連結性の診断結果
+type GraphReport struct {
+	// スタートから (経由地を順に通って) いずれかのゴールへ行けるか。TSP では全ノードへ行けるか
+	Reachable bool `json:"reachable"`
+	// 連結成分 (辺の向きを無視。大きい順、各成分のノードは ID 順)
+	Components [][]int `json:"components"`
+	// 辺が1本もないノード
+	Isolated []int `json:"isolated"`
+	// スタートから辺の向きに従って行けないノード
+	Unreachable []int `json:"unreachable"`
+	// 読み込み時に取り除いた重複辺 (From, To。無向辺は逆向きも重複とみなす)
+	DuplicateEdges [][2]int `json:"duplicateEdges"`
+	// 連結成分をつなぐために追加した辺 (Config.ConnectComponents)
+	AddedEdges [][2]int `json:"addedEdges"`
+}
+
+// CheckGraph: 現在のグラフとルートの連結性を診断する
+func (aco *ACO) CheckGraph() GraphReport {
+	n := len(aco.Graph.Nodes)
+	report := GraphReport{
+		Components:     aco.components(),
+		Isolated:       []int{},
+		Unreachable:    []int{},
+		DuplicateEdges: append([][2]int{}, aco.duplicateEdges...),
+		AddedEdges:     [][2]int{},
+	}
+	for _, e := range aco.Graph.Edges {
+		if e.Bridge {
+			report.AddedEdges = append(report.AddedEdges, [2]int{e.From, e.To})
+		}
+	}
+	for _, component := range report.Components {
+		if len(component) == 1 && len(aco.Adj[component[0]]) == 0 {
+			report.Isolated = append(report.Isolated, component[0])
+		}
+	}
+	sort.Ints(report.Isolated)
+
+	fromStart := aco.reachableFrom(aco.StartNode)
+	for v := 0; v < n; v++ {
+		if !fromStart[v] {
+			report.Unreachable = append(report.Unreachable, v)
+		}
+	}
+	if aco.Config.Mode == ModeTSP {
+		report.Reachable = len(report.Unreachable) == 0
+		return report
+	}
+
+	// 区間ごとに、直前の区間の行き先から次の行き先へ行けるかを調べる
+	report.Reachable = true
+	source := aco.StartNode
+	for leg := 0; leg <= len(aco.Waypoints); leg++ {
+		reached := aco.reachableFrom(source)
+		next := -1
+		for _, target := range aco.legTargets(leg) {
+			if reached[target] {
+				next = target
+				break
+			}
+		}
+		if next == -1 {
+			report.Reachable = false
+			break
+		}
+		source = next
+	}
+	return report
+}
+
+// reachableFrom: source から辺の向きに従って行けるノード (幅優先探索)
+func (aco *ACO) reachableFrom(source int) []bool {
+	reached := make([]bool, len(aco.Graph.Nodes))
+	reached[source] = true
+	queue := []int{source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, nb := range aco.Adj[u] {
+			if !reached[nb.To] {
+				reached[nb.To] = true
+				queue = append(queue, nb.To)
+			}
+		}
+	}
+	return reached
+}
+
+// components: 辺の向きを無視した連結成分 (大きい順、同じ大きさなら最小の ID 順)
+func (aco *ACO) components() [][]int {
+	return graphComponents(len(aco.Graph.Nodes), aco.Graph.Edges)
+}
+
+// graphComponents: n 個のノードと edges からなるグラフの連結成分 (components と同じ順)
+func graphComponents(n int, edges []Edge) [][]int {
+	undirected := make([][]int, n)
+	for _, e := range edges {
+		undirected[e.From] = append(undirected[e.From], e.To)
+		undirected[e.To] = append(undirected[e.To], e.From)
+	}
+
+	seen := make([]bool, n)
+	var components [][]int
+	for root := 0; root < n; root++ {
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		component := []int{root}
+		for i := 0; i < len(component); i++ {
+			for _, v := range undirected[component[i]] {
+				if !seen[v] {
+					seen[v] = true
+					component = append(component, v)
+				}
+			}
+		}
+		sort.Ints(component)
+		components = append(components, component)
+	}
+	sort.SliceStable(components, func(i, j int) bool { return len(components[i]) > len(components[j]) })
+	return components
+}
+
+// findDuplicateEdges: normalizeGraph が取り除く重複辺 (同じ向きの辺、または無向辺と重なる辺)
+func findDuplicateEdges(edges []Edge) [][2]int {
+	linked := make(map[[2]int]bool)
+	var duplicates [][2]int
+	for _, e := range edges {
+		forward, backward := [2]int{e.From, e.To}, [2]int{e.To, e.From}
+		if linked[forward] || (!e.Directed && linked[backward]) {
+			duplicates = append(duplicates, forward)
+			continue
+		}
+		linked[forward] = true
+		if !e.Directed {
+			linked[backward] = true
+		}
+	}
+	return duplicates
+}
+
+// connectComponents: 連結成分が1つになるまで、成分どうしを座標上で最も近いノードの組で結ぶ無向辺を足す
+// (成分を1つの点とみなした最小全域木になるので、足す辺は成分の数 - 1 本)。
+// 最大の成分から Prim 法で広げ、各ノードについて木までの最短距離を持つので O(n²)。
+// 重みを指定した辺があれば、足す辺の重みはそれらの最小の (重み/長さ) 比で長さから求める
+// (なければ 0 のままにして、他の辺と同じく fillWeights で正規化する)。
+// 障害物は考えない (つながらないグラフを残さないことを優先する)
+func connectComponents(graph *GraphData, space space) {
+	n := len(graph.Nodes)
+	components := graphComponents(n, graph.Edges)
+	if len(components) <= 1 {
+		return
+	}
+	unitWeight := math.Inf(1)
+	for _, e := range graph.Edges {
+		if length := space.distance(graph.Nodes[e.From], graph.Nodes[e.To]); e.Weight > 0 && length > 0 {
+			unitWeight = math.Min(unitWeight, e.Weight/length)
+		}
+	}
+	if math.IsInf(unitWeight, 1) {
+		unitWeight = 0
+	}
+	componentOf := make([]int, n)
+	for c, component := range components {
+		for _, v := range component {
+			componentOf[v] = c
+		}
+	}
+
+	inTree := make([]bool, n)
+	nearest := make([]float64, n) // 木に入っていないノードから木までの最短距離
+	from := make([]int, n)        // その距離を与える木の側のノード
+	for v := range nearest {
+		nearest[v] = math.Inf(1)
+	}
+	add := func(component []int) {
+		for _, u := range component {
+			inTree[u] = true
+		}
+		for _, u := range component {
+			for v := 0; v < n; v++ {
+				if d := space.distance(graph.Nodes[u], graph.Nodes[v]); !inTree[v] && d < nearest[v] {
+					nearest[v], from[v] = d, u
+				}
+			}
+		}
+	}
+
+	add(components[0])
+	for joined := 1; joined < len(components); joined++ {
+		next := -1
+		for v := 0; v < n; v++ {
+			if !inTree[v] && (next == -1 || nearest[v] < nearest[next]) {
+				next = v
+			}
+		}
+		rawDist := nearest[next]
+		graph.Edges = append(graph.Edges, Edge{From: from[next], To: next, Weight: rawDist * unitWeight, RawDist: rawDist, Bridge: true})
+		add(components[componentOf[next]])
+	}
+}
@@ -0,0 +1,69 @@
+package solver
+
+import (
+	"math"
+	"slices"
+)
+
+// 差分出力 (大きなグラフで毎イテレーション全ての辺を送らないためのもの)
+// 前回の差分で送った値を覚えておき、そこから threshold * (現在の最大フェロモン量) を超えて変わった辺だけを返す。
+// 描画は最大値に対する比で濃さを決めるので、しきい値未満の変化は見た目にほとんど影響しない
+// (全ての辺が一様に減る蒸発だけでは送らない)。
+// 小さな変化は送った値との差として積み重なるので、ゆっくりした変化もいずれ送られる。
+// 初回とグラフの辺が変わった後は全ての辺を返す (Full)。
+
+// DeltaThreshold: 既定のしきい値 (最大フェロモン量に対する比)
+const DeltaThreshold = 0.05
+
+// EdgeChange: 前回の差分からフェロモン量が変わった辺 (Edge は Graph.Edges の位置)
+type EdgeChange struct {
+	Edge  int     `json:"edge"`
+	Value float64 `json:"value"`
+}
+
+// PheromoneDelta: 前回の差分以降の変化
+type PheromoneDelta struct {
+	Full        bool         `json:"full"`        // 全ての辺を含む (最初の差分か辺の変更後。受け取り側は置き換える)
+	Changes     []EdgeChange `json:"changes"`     // 前回送った値から threshold × Max より大きく動いた辺だけ
+	Max         float64      `json:"max"`         // 現在の最大フェロモン量
+	BestChanged bool         `json:"bestChanged"` // ベスト経路が変わった
+}
+
+// deltaState: 前回の差分で送った状態
+type deltaState struct {
+	edges      []Edge    // 送ったときの辺 (グラフの変更の検出用)
+	pheromones []float64 // 送った値 (Graph.Edges と同順)
+	bestPath   []int
+}
+
+// Delta: 前回の Delta 以降に threshold * 最大フェロモン量 を超えて変わった辺と、ベスト経路が変わったかを返す
+func (aco *ACO) Delta(threshold float64) PheromoneDelta {
+	state := &aco.delta
+	delta := PheromoneDelta{Changes: []EdgeChange{}, BestChanged: !slices.Equal(state.bestPath, aco.BestPath)}
+	state.bestPath = append([]int(nil), aco.BestPath...)
+
+	if !sameEdges(state.edges, aco.Graph.Edges) {
+		delta.Full = true
+		state.edges = append([]Edge(nil), aco.Graph.Edges...)
+		state.pheromones = make([]float64, len(aco.Graph.Edges))
+	}
+	values := make([]float64, len(aco.Graph.Edges))
+	for i, e := range aco.Graph.Edges {
+		values[i] = aco.pheromone(e.From, e.To)
+		delta.Max = math.Max(delta.Max, values[i])
+	}
+	for i, value := range values {
+		if delta.Full || math.Abs(value-state.pheromones[i]) > threshold*delta.Max {
+			delta.Changes = append(delta.Changes, EdgeChange{Edge: i, Value: value})
+			state.pheromones[i] = value
+		}
+	}
+	return delta
+}
+
+// sameEdges: 辺の並びと端点・向きが同じか
+func sameEdges(a, b []Edge) bool {
+	return slices.EqualFunc(a, b, func(x, y Edge) bool {
+		return x.From == y.From && x.To == y.To && x.Directed == y.Directed
+	})
+}
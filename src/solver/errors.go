@@ -0,0 +1,12 @@
+package solver
+
+import "errors"
+
+// エラーの種類 (errors.Is で判別し、JS側のエラーコードに対応させる)
+var (
+	ErrInvalidConfig = errors.New("invalid config")
+	ErrInvalidGraph  = errors.New("invalid graph")
+	ErrInvalidNode   = errors.New("invalid node")
+	ErrUnreachable   = errors.New("unreachable")
+	ErrNegativeCycle = errors.New("negative cycle")
+)
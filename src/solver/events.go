@@ -0,0 +1,104 @@
+package solver
+
+import (
+	"fmt"
+	"sort"
+)
+
+// イベントの通知 (呼び出し側がポーリングせずに、Step の中で起きたことを受け取れるようにする)
+// On で登録したハンドラは、Step の終わりにその Step で起きたイベントの順に呼ばれる。
+// マルチコロニーでは親のインスタンスに登録し、リスタートはコロニーごとに通知する。
+// 登録はインスタンスに属するので、スナップショットには含まれない。
+
+// イベント名
+const (
+	EventImprovement = "improvement" // ベスト経路が更新された
+	EventRestart     = "restart"     // 停滞によるリスタート (Config.RestartAfter)
+	EventConverged   = "converged"   // 収束と判定された (Config.StagnationLimit、改善の後は再び通知する)
+)
+
+// eventNames: 登録できるイベント名
+var eventNames = map[string]bool{EventImprovement: true, EventRestart: true, EventConverged: true}
+
+// Event: ハンドラに渡すイベント
+type Event struct {
+	Name       string  `json:"event"`
+	Iteration  int     `json:"iteration"`
+	BestDist   float64 `json:"bestDist"`
+	BestPath   []int   `json:"bestPath"`
+	Stagnation int     `json:"stagnation"`
+	Colony     *int    `json:"colony,omitempty"` // リスタートしたコロニー (マルチコロニー時のみ)
+}
+
+// EventNames: 登録できるイベント名 (ソート済み)
+func EventNames() []string {
+	names := make([]string, 0, len(eventNames))
+	for name := range eventNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// On: name のイベントが起きたときに handler を呼ぶ (同じイベントに複数登録でき、登録順に呼ぶ)
+func (aco *ACO) On(name string, handler func(Event)) error {
+	if !eventNames[name] {
+		return fmt.Errorf("%w: unknown event %q (available: %v)", ErrInvalidConfig, name, EventNames())
+	}
+	if aco.handlers == nil {
+		aco.handlers = map[string][]func(Event){}
+	}
+	aco.handlers[name] = append(aco.handlers[name], handler)
+	return nil
+}
+
+// Off: name のイベントのハンドラを全て外す (name が空なら全てのイベント)
+func (aco *ACO) Off(name string) error {
+	if name == "" {
+		aco.handlers = nil
+		return nil
+	}
+	if !eventNames[name] {
+		return fmt.Errorf("%w: unknown event %q (available: %v)", ErrInvalidConfig, name, EventNames())
+	}
+	delete(aco.handlers, name)
+	return nil
+}
+
+// converged: StagnationLimit イテレーション改善がないか (Convergence の判定)
+func (aco *ACO) converged() bool {
+	return aco.Config.StagnationLimit > 0 && aco.BestPath != nil && aco.Stagnation >= aco.Config.StagnationLimit
+}
+
+// emitEvents: 終わった Step で起きたイベントをハンドラに通知する (wasConverged は Step の前の判定)
+func (aco *ACO) emitEvents(wasConverged bool) {
+	event := func(name string) Event {
+		return Event{Name: name, Iteration: aco.Iteration, BestDist: aco.BestDist, BestPath: aco.BestPath, Stagnation: aco.Stagnation}
+	}
+	if h := aco.History; len(h) > 0 && h[len(h)-1].Iteration == aco.Iteration {
+		aco.emit(event(EventImprovement))
+	}
+	// 同じ Step で複数のコロニーがリスタートしうる
+	first := len(aco.Stats.Restarts)
+	for first > 0 && aco.Stats.Restarts[first-1].Iteration == aco.Iteration {
+		first--
+	}
+	for _, restart := range aco.Stats.Restarts[first:] {
+		e := event(EventRestart)
+		if len(aco.Colonies) > 0 {
+			colony := restart.Colony
+			e.Colony = &colony
+		}
+		aco.emit(e)
+	}
+	if !wasConverged && aco.converged() {
+		aco.emit(event(EventConverged))
+	}
+}
+
+// emit: name のハンドラを登録順に呼ぶ
+func (aco *ACO) emit(e Event) {
+	for _, handler := range aco.handlers[e.Name] {
+		handler(e)
+	}
+}
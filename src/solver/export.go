@@ -0,0 +1,122 @@
+package solver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// グラフのエクスポート (Gephi / Graphviz でのオフライン分析用)
+// 辺には重みと現在のフェロモン量を属性として付ける。
+
+// エクスポート形式
+const (
+	FormatGraphML = "graphml"
+	FormatDOT     = "dot"
+)
+
+// graphExporters: 形式名ごとのエクスポート関数
+var graphExporters = map[string]func(aco *ACO) string{
+	FormatGraphML: exportGraphML,
+	FormatDOT:     exportDOT,
+}
+
+// ExportFormats: 対応しているエクスポート形式 (ソート済み)
+func ExportFormats() []string {
+	formats := make([]string, 0, len(graphExporters))
+	for format := range graphExporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ExportGraph: 現在のグラフとフェロモンを指定形式の文字列にする
+func (aco *ACO) ExportGraph(format string) (string, error) {
+	export, ok := graphExporters[format]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown export format %q (available: %v)", ErrInvalidConfig, format, ExportFormats())
+	}
+	return export(aco), nil
+}
+
+// exportGraphML: GraphML 形式 (一方通行の辺は directed="true")
+func exportGraphML(aco *ACO) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	b.WriteString(`  <key id="x" for="node" attr.name="x" attr.type="double"/>` + "\n")
+	b.WriteString(`  <key id="y" for="node" attr.name="y" attr.type="double"/>` + "\n")
+	if aco.Graph.Is3D {
+		b.WriteString(`  <key id="z" for="node" attr.name="z" attr.type="double"/>` + "\n")
+	}
+	b.WriteString(`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="color" for="node" attr.name="color" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="weight" for="edge" attr.name="weight" attr.type="double"/>` + "\n")
+	b.WriteString(`  <key id="rawDist" for="edge" attr.name="rawDist" attr.type="double"/>` + "\n")
+	b.WriteString(`  <key id="pheromone" for="edge" attr.name="pheromone" attr.type="double"/>` + "\n")
+	b.WriteString(`  <graph id="G" edgedefault="undirected">` + "\n")
+	for _, n := range aco.Graph.Nodes {
+		fmt.Fprintf(&b, `    <node id="n%d"><data key="x">%g</data><data key="y">%g</data>`, n.ID, n.X, n.Y)
+		if aco.Graph.Is3D {
+			fmt.Fprintf(&b, `<data key="z">%g</data>`, n.Z)
+		}
+		if n.Label != "" {
+			fmt.Fprintf(&b, `<data key="label">%s</data>`, xmlEscape(n.Label))
+		}
+		if n.Color != "" {
+			fmt.Fprintf(&b, `<data key="color">%s</data>`, xmlEscape(n.Color))
+		}
+		b.WriteString("</node>\n")
+	}
+	for i, e := range aco.Graph.Edges {
+		directed := ""
+		if e.Directed {
+			directed = ` directed="true"`
+		}
+		fmt.Fprintf(&b, `    <edge id="e%d" source="n%d" target="n%d"%s><data key="weight">%g</data><data key="rawDist">%g</data><data key="pheromone">%g</data></edge>`+"\n",
+			i, e.From, e.To, directed, e.Weight, e.RawDist, aco.pheromone(e.From, e.To))
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.String()
+}
+
+// xmlEscape: 属性値や文字データに入れられるよう XML の特殊文字を置き換える
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// exportDOT: Graphviz DOT 形式
+// 一方通行の辺があれば digraph にし、無向辺は dir=none で表す
+func exportDOT(aco *ACO) string {
+	directed := aco.hasOneWayEdges()
+	keyword, arrow := "graph", "--"
+	if directed {
+		keyword, arrow = "digraph", "->"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s G {\n", keyword)
+	for _, n := range aco.Graph.Nodes {
+		fmt.Fprintf(&b, "  %d [pos=\"%g,%g!\"", n.ID, n.X, n.Y)
+		if n.Label != "" {
+			fmt.Fprintf(&b, ", label=%q", n.Label)
+		}
+		if n.Color != "" {
+			fmt.Fprintf(&b, ", color=%q", n.Color)
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range aco.Graph.Edges {
+		dir := ""
+		if directed && !e.Directed {
+			dir = ", dir=none"
+		}
+		fmt.Fprintf(&b, "  %d %s %d [weight=\"%g\", rawDist=\"%g\", pheromone=\"%g\"%s];\n", e.From, arrow, e.To, e.Weight, e.RawDist, aco.pheromone(e.From, e.To), dir)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,266 @@
+package solver
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// 遺伝的アルゴリズム (比較用、経路探索モードのみ)
+// 個体は焼きなまし法と同じくスタート→経由地→ゴールの区間ごとの単純路。
+// 交叉は同じ区間で両親が共有するノードを境に前半と後半をつなぎ、区間ごとにどちらかの親から受け継ぐ。
+// 突然変異は焼きなまし法の近傍 (部分経路の迂回路への置き換え) を使う。
+
+// 遺伝的アルゴリズムの既定のパラメータ
+const (
+	GAPopulation    = 50
+	GACrossoverRate = 0.9
+	GAMutationRate  = 0.2
+	GAElitism       = 2
+	GATournament    = 3
+)
+
+// GAConfig: StepGA のパラメータ
+type GAConfig struct {
+	Population    int     `json:"population"`
+	CrossoverRate float64 `json:"crossoverRate"`
+	MutationRate  float64 `json:"mutationRate"`
+	// そのまま次世代に残す上位個体の数
+	Elitism int `json:"elitism"`
+	// トーナメント選択で比べる個体数
+	Tournament int `json:"tournament"`
+	// 乱数シード (省略時はインスタンスの Seed)
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+func DefaultGAConfig() GAConfig {
+	return GAConfig{
+		Population:    GAPopulation,
+		CrossoverRate: GACrossoverRate,
+		MutationRate:  GAMutationRate,
+		Elitism:       GAElitism,
+		Tournament:    GATournament,
+	}
+}
+
+// Validate: 世代交代が破綻する値を弾く
+func (c GAConfig) Validate() error {
+	if c.Population < 2 {
+		return fmt.Errorf("%w: population must be >= 2 (got %d)", ErrInvalidConfig, c.Population)
+	}
+	if c.CrossoverRate < 0 || c.CrossoverRate > 1 || c.MutationRate < 0 || c.MutationRate > 1 {
+		return fmt.Errorf("%w: crossoverRate and mutationRate must be in [0, 1] (got %g, %g)", ErrInvalidConfig, c.CrossoverRate, c.MutationRate)
+	}
+	if c.Elitism < 0 || c.Elitism >= c.Population {
+		return fmt.Errorf("%w: elitism must be in [0, population) (got %d)", ErrInvalidConfig, c.Elitism)
+	}
+	if c.Tournament < 1 {
+		return fmt.Errorf("%w: tournament must be >= 1 (got %d)", ErrInvalidConfig, c.Tournament)
+	}
+	return nil
+}
+
+// GA: 世代をまたいで保持する遺伝的アルゴリズムの状態
+type GA struct {
+	Config     GAConfig
+	Generation int
+	BestDist   float64
+	BestPath   []int
+	bestLegs   [][]int
+	population []gaIndividual
+	rand       *rand.Rand
+}
+
+type gaIndividual struct {
+	legs [][]int // 区間ごとの単純路 (スライスは個体間で共有するので書き換えない)
+	dist float64
+}
+
+// GAResult: 1世代分の結果
+type GAResult struct {
+	Generation int     `json:"generation"`
+	BestDist   float64 `json:"bestDist"`
+	BestPath   []int   `json:"bestPath"`
+	AvgDist    float64 `json:"avgDist"` // 現世代の平均距離
+}
+
+// StepGA: 遺伝的アルゴリズムを1世代進める
+// cfg を渡すと新しい集団で始め直す (最初の呼び出しで nil なら既定値)。
+// グラフや経由地・ゴールの変更で無効になった個体はランダムな経路で置き換える。
+func (aco *ACO) StepGA(cfg *GAConfig) (GAResult, error) {
+	if aco.Config.Mode != ModeRoute {
+		return GAResult{}, fmt.Errorf("%w: the genetic algorithm is only supported in %q mode", ErrInvalidConfig, ModeRoute)
+	}
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return GAResult{}, err
+	}
+	if cfg != nil || aco.ga == nil {
+		gaConfig := DefaultGAConfig()
+		if cfg != nil {
+			gaConfig = *cfg
+		}
+		if err := gaConfig.Validate(); err != nil {
+			return GAResult{}, err
+		}
+		seed := aco.Seed
+		if gaConfig.Seed != nil {
+			seed = *gaConfig.Seed
+		}
+		rng, _ := newRand(seed)
+		aco.ga = &GA{Config: gaConfig, rand: rng}
+	}
+
+	ga := aco.ga
+	if err := aco.repairPopulation(ga); err != nil {
+		return GAResult{}, err
+	}
+	sort.SliceStable(ga.population, func(a, b int) bool { return ga.population[a].dist < ga.population[b].dist })
+
+	next := append([]gaIndividual(nil), ga.population[:ga.Config.Elitism]...)
+	for len(next) < ga.Config.Population {
+		child := ga.tournament()
+		child.legs = append([][]int(nil), child.legs...)
+		if ga.rand.Float64() < ga.Config.CrossoverRate {
+			child.legs = crossoverLegs(child.legs, ga.tournament().legs, ga.rand)
+		}
+		if ga.rand.Float64() < ga.Config.MutationRate {
+			if l, candidate := aco.rerouteSegment(child.legs, ga.rand); candidate != nil {
+				child.legs[l] = candidate
+			}
+		}
+		child.dist = aco.calculatePathDistance(joinLegs(child.legs))
+		next = append(next, child)
+	}
+	ga.population = next
+	ga.Generation++
+
+	total := 0.0
+	for _, individual := range ga.population {
+		total += individual.dist
+		if ga.bestLegs == nil || individual.dist < ga.BestDist {
+			ga.BestDist, ga.BestPath, ga.bestLegs = individual.dist, joinLegs(individual.legs), individual.legs
+		}
+	}
+	return GAResult{
+		Generation: ga.Generation,
+		BestDist:   ga.BestDist,
+		BestPath:   ga.BestPath,
+		AvgDist:    total / float64(len(ga.population)),
+	}, nil
+}
+
+// repairPopulation: 集団を Population 個の有効な個体で満たす (初期集団の生成も兼ねる)
+// 距離は現在の重みで測り直し、無効になったベストは捨てる
+func (aco *ACO) repairPopulation(ga *GA) error {
+	valid := ga.population[:0]
+	for _, individual := range ga.population {
+		if aco.validLegs(individual.legs) {
+			individual.dist = aco.calculatePathDistance(joinLegs(individual.legs))
+			valid = append(valid, individual)
+		}
+	}
+	ga.population = valid
+	if ga.bestLegs != nil && aco.validLegs(ga.bestLegs) {
+		ga.BestDist = aco.calculatePathDistance(ga.BestPath)
+	} else {
+		ga.BestDist, ga.BestPath, ga.bestLegs = 0, nil, nil
+	}
+
+	for len(ga.population) < ga.Config.Population {
+		legs := make([][]int, len(aco.Waypoints)+1)
+		source := aco.StartNode
+		for l := range legs {
+			targets := aco.legTargets(l)
+			if legs[l] = aco.randomRoute(source, targets, make([]bool, len(aco.Adj)), ga.rand); legs[l] == nil {
+				return fmt.Errorf("%w: no path from %d to %v", ErrUnreachable, source, targets)
+			}
+			source = legs[l][len(legs[l])-1]
+		}
+		ga.population = append(ga.population, gaIndividual{legs: legs, dist: aco.calculatePathDistance(joinLegs(legs))})
+	}
+	return nil
+}
+
+// tournament: ランダムに選んだ Tournament 個体のうち最も短いもの
+func (ga *GA) tournament() gaIndividual {
+	best := ga.population[ga.rand.Intn(len(ga.population))]
+	for i := 1; i < ga.Config.Tournament; i++ {
+		if challenger := ga.population[ga.rand.Intn(len(ga.population))]; challenger.dist < best.dist {
+			best = challenger
+		}
+	}
+	return best
+}
+
+// crossoverLegs: 区間ごとに a か b を受け継ぎ、ランダムな1区間は共有ノードで a の前半と b の後半をつなぐ
+func crossoverLegs(a, b [][]int, rng *rand.Rand) [][]int {
+	child := make([][]int, len(a))
+	for l := range child {
+		child[l] = a[l]
+		if rng.Intn(2) == 0 {
+			child[l] = b[l]
+		}
+	}
+
+	l := rng.Intn(len(a))
+	positions := make(map[int]int, len(b[l]))
+	for i, v := range b[l][1:] {
+		positions[v] = i + 1
+	}
+	var cuts [][2]int // (a での位置, b での位置)
+	for i, v := range a[l][1 : len(a[l])-1] {
+		if j, ok := positions[v]; ok && j < len(b[l])-1 {
+			cuts = append(cuts, [2]int{i + 1, j})
+		}
+	}
+	if len(cuts) == 0 {
+		return child
+	}
+	cut := cuts[rng.Intn(len(cuts))]
+	spliced := append(append([]int(nil), a[l][:cut[0]]...), b[l][cut[1]:]...)
+	child[l] = removeLoops(spliced)
+	return child
+}
+
+// removeLoops: 同じノードを2回通る部分を切り取って単純路にする
+func removeLoops(path []int) []int {
+	positions := make(map[int]int, len(path))
+	result := path[:0]
+	for _, v := range path {
+		if i, ok := positions[v]; ok {
+			for _, w := range result[i+1:] {
+				delete(positions, w)
+			}
+			result = result[:i+1]
+			continue
+		}
+		positions[v] = len(result)
+		result = append(result, v)
+	}
+	return result
+}
+
+// validLegs: 区間の列が現在のスタート・経由地・ゴールと辺に沿っているか
+func (aco *ACO) validLegs(legs [][]int) bool {
+	if len(legs) != len(aco.Waypoints)+1 {
+		return false
+	}
+	source := aco.StartNode
+	for l, leg := range legs {
+		if len(leg) == 0 || leg[0] != source || !aco.reachedLeg(l, leg[len(leg)-1]) || !aco.validRoute(leg) {
+			return false
+		}
+		source = leg[len(leg)-1]
+	}
+	return true
+}
+
+// validRoute: 経路の隣り合うノードが全て辺でつながっているか
+func (aco *ACO) validRoute(path []int) bool {
+	for i := 0; i+1 < len(path); i++ {
+		if path[i] < 0 || path[i] >= len(aco.Adj) || !aco.hasEdge(path[i], path[i+1]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package solver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// GeoJSON の読み込み
+// Point を ノード、LineString / MultiLineString の隣り合う頂点を辺にする。
+// 同じ座標の頂点は同じノードとして扱うので、道路の交差点でつながる。
+// 経度・緯度は中心緯度での正距円筒図法で平面に投影し、座標空間 (GeoJSONSize 四方) に収める。
+// 辺の重みは省略し、normalizeGraph で投影後の長さ (実距離に比例) を使う。
+// Point の properties のうち name (または label) と color (または marker-color) はノードの表示名と色、
+// 数値の cost は通過コスト、残りは meta にする。
+
+// GeoJSONSize: 投影後の座標空間の一辺 (生成グラフと同じ 0..100)
+const GeoJSONSize = 100.0
+
+type geoJSON struct {
+	Type     string       `json:"type"`
+	Features []geoFeature `json:"features"`
+}
+
+type geoFeature struct {
+	Geometry   *geoGeometry           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// DecodeGraph: {nodes, edges} 形式または GeoJSON FeatureCollection をグラフにする
+func DecodeGraph(data []byte) (GraphData, error) {
+	var probe struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return GraphData{}, err
+	}
+	if probe.Type == "FeatureCollection" {
+		return ParseGeoJSON(data)
+	}
+
+	var graph GraphData
+	err := json.Unmarshal(data, &graph)
+	return graph, err
+}
+
+// ParseGeoJSON: FeatureCollection からグラフを作る (Point / LineString / MultiLineString 以外は無視)
+func ParseGeoJSON(data []byte) (GraphData, error) {
+	var collection geoJSON
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return GraphData{}, err
+	}
+	if collection.Type != "FeatureCollection" {
+		return GraphData{}, fmt.Errorf("%w: expected a GeoJSON FeatureCollection (got type %q)", ErrInvalidGraph, collection.Type)
+	}
+
+	var positions [][2]float64 // ノードID順の [経度, 緯度]
+	ids := make(map[[2]float64]int)
+	nodeAt := func(position []float64) (int, error) {
+		if len(position) < 2 {
+			return -1, errors.New("GeoJSON position needs [lon, lat]")
+		}
+		key := [2]float64{position[0], position[1]}
+		if id, ok := ids[key]; ok {
+			return id, nil
+		}
+		ids[key] = len(positions)
+		positions = append(positions, key)
+		return ids[key], nil
+	}
+
+	properties := make(map[int]map[string]interface{}) // Point のノードID → properties
+
+	var edges []Edge
+	linked := make(map[[2]int]bool)
+	addLine := func(line [][]float64) error {
+		prev := -1
+		for _, position := range line {
+			id, err := nodeAt(position)
+			if err != nil {
+				return err
+			}
+			if prev != -1 && prev != id && !linked[edgeKey(prev, id)] {
+				linked[edgeKey(prev, id)] = true
+				edges = append(edges, Edge{From: prev, To: id})
+			}
+			prev = id
+		}
+		return nil
+	}
+
+	for i, feature := range collection.Features {
+		if feature.Geometry == nil {
+			continue
+		}
+		var err error
+		switch feature.Geometry.Type {
+		case "Point":
+			var position []float64
+			if err = json.Unmarshal(feature.Geometry.Coordinates, &position); err == nil {
+				var id int
+				if id, err = nodeAt(position); err == nil && len(feature.Properties) > 0 {
+					properties[id] = feature.Properties
+				}
+			}
+		case "LineString":
+			var line [][]float64
+			if err = json.Unmarshal(feature.Geometry.Coordinates, &line); err == nil {
+				err = addLine(line)
+			}
+		case "MultiLineString":
+			var lines [][][]float64
+			if err = json.Unmarshal(feature.Geometry.Coordinates, &lines); err == nil {
+				for _, line := range lines {
+					if err = addLine(line); err != nil {
+						break
+					}
+				}
+			}
+		}
+		if err != nil {
+			return GraphData{}, fmt.Errorf("%w: feature %d: %v", ErrInvalidGraph, i, err)
+		}
+	}
+
+	nodes := projectLonLat(positions)
+	for id, props := range properties {
+		applyProperties(&nodes[id], props)
+	}
+	return GraphData{Nodes: nodes, Edges: edges}, nil
+}
+
+// applyProperties: Point の properties をノードの表示名・色・meta に振り分ける
+func applyProperties(node *Node, properties map[string]interface{}) {
+	take := func(keys ...string) string {
+		for _, key := range keys {
+			if s, ok := properties[key].(string); ok {
+				return s
+			}
+		}
+		return ""
+	}
+	node.Label = take("name", "label")
+	node.Color = take("color", "marker-color")
+	if cost, ok := properties["cost"].(float64); ok {
+		node.Cost = cost
+	}
+	for key, value := range properties {
+		if _, ok := value.(string); ok && (key == "name" || key == "label" || key == "color" || key == "marker-color") {
+			continue
+		}
+		if _, ok := value.(float64); ok && key == "cost" {
+			continue
+		}
+		if node.Meta == nil {
+			node.Meta = make(map[string]interface{})
+		}
+		node.Meta[key] = value
+	}
+}
+
+// projectLonLat: 経度・緯度を中心緯度での正距円筒図法で投影し、縦横比を保って GeoJSONSize 四方に収める
+// y は画面と同じく下向き (北が上)
+func projectLonLat(positions [][2]float64) []Node {
+	if len(positions) == 0 {
+		return nil
+	}
+
+	minLon, maxLon := positions[0][0], positions[0][0]
+	minLat, maxLat := positions[0][1], positions[0][1]
+	for _, p := range positions {
+		minLon, maxLon = math.Min(minLon, p[0]), math.Max(maxLon, p[0])
+		minLat, maxLat = math.Min(minLat, p[1]), math.Max(maxLat, p[1])
+	}
+	cosLat := math.Cos((minLat + maxLat) / 2 * math.Pi / 180)
+
+	span := math.Max((maxLon-minLon)*cosLat, maxLat-minLat)
+	scale := 1.0
+	if span > 0 {
+		scale = GeoJSONSize / span
+	}
+
+	nodes := make([]Node, len(positions))
+	for i, p := range positions {
+		nodes[i] = Node{
+			ID: i,
+			X:  (p[0] - minLon) * cosLat * scale,
+			Y:  (maxLat - p[1]) * scale,
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,53 @@
+package solver
+
+import "fmt"
+
+// 複数ゴール (経路探索モードのみ)
+// いずれかのゴールに着いたアリを成功とみなす (最寄りの出口を探すような場面向け)。
+// GoalNode は Goals の先頭で、単一ゴールのときは Goals を nil のままにする。
+
+// SetGoals: ゴールの集合を設定し、ベスト経路をリセットする
+func (aco *ACO) SetGoals(goals []int) error {
+	if len(goals) == 0 {
+		return fmt.Errorf("%w: at least one goal is required", ErrInvalidNode)
+	}
+	if len(goals) > 1 && aco.Config.Mode == ModeTSP {
+		return fmt.Errorf("%w: multiple goals are only supported in %q mode", ErrInvalidConfig, ModeRoute)
+	}
+	for _, id := range goals {
+		if err := aco.checkNode(id); err != nil {
+			return err
+		}
+		if id == aco.StartNode {
+			return fmt.Errorf("%w: goal %d is the start node", ErrInvalidNode, id)
+		}
+	}
+
+	aco.GoalNode = goals[0]
+	aco.Goals = nil
+	if len(goals) > 1 {
+		aco.Goals = append([]int(nil), goals...)
+	}
+	aco.clearBest()
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.SetGoals(goals)
+	})
+}
+
+// goals: 現在のゴールの集合
+func (aco *ACO) goals() []int {
+	if len(aco.Goals) == 0 {
+		return []int{aco.GoalNode}
+	}
+	return aco.Goals
+}
+
+// isGoal: id がいずれかのゴールか
+func (aco *ACO) isGoal(id int) bool {
+	for _, g := range aco.goals() {
+		if g == id {
+			return true
+		}
+	}
+	return false
+}
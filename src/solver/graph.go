@@ -0,0 +1,477 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// NewACOFromGraph: ユーザー指定のグラフからACOを構築する
+func NewACOFromGraph(graph GraphData, cfg Config) (*ACO, error) {
+	normalized, err := normalizeGraph(graph, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := cfg.resolveSeed()
+	randSource, src := newSearchRand(cfg, seed)
+	aco := newACO(normalized, cfg, seed, randSource, src)
+	aco.duplicateEdges = findDuplicateEdges(graph.Edges)
+	return aco, nil
+}
+
+// normalizeGraph: ID の検証・並べ替え、辺の検証と重複除去を行う
+// ノードIDは 0..n-1 の連番である必要がある (JS側は nodes[id] で参照するため)
+// 重みを省略した辺は cfg.Normalization に従って座標上の長さから求める
+func normalizeGraph(graph GraphData, cfg Config) (GraphData, error) {
+	n := len(graph.Nodes)
+	if n < 2 {
+		return GraphData{}, fmt.Errorf("%w: graph needs at least 2 nodes (got %d)", ErrInvalidGraph, n)
+	}
+
+	nodes := make([]Node, n)
+	copy(nodes, graph.Nodes)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	for i, node := range nodes {
+		if node.ID != i {
+			return GraphData{}, fmt.Errorf("%w: node ids must be 0..%d without gaps or duplicates (unexpected id %d)", ErrInvalidGraph, n-1, node.ID)
+		}
+		if node.Cost < 0 || math.IsInf(node.Cost, 0) || math.IsNaN(node.Cost) {
+			return GraphData{}, fmt.Errorf("%w: node %d has invalid cost %g (must be finite and >= 0)", ErrInvalidGraph, node.ID, node.Cost)
+		}
+		if node.Prize < 0 || math.IsInf(node.Prize, 0) || math.IsNaN(node.Prize) {
+			return GraphData{}, fmt.Errorf("%w: node %d has invalid prize %g (must be finite and >= 0)", ErrInvalidGraph, node.ID, node.Prize)
+		}
+	}
+
+	edges := make([]Edge, 0, len(graph.Edges))
+	space := newSpace(graph.Torus, cfg)
+	// 半辺 (From→To) ごとの使用済み表。無向辺は両向きを使う
+	linked := make(map[[2]int]bool)
+	for _, e := range graph.Edges {
+		if e.From < 0 || e.From >= n || e.To < 0 || e.To >= n {
+			return GraphData{}, fmt.Errorf("%w: edge %d-%d references unknown node", ErrInvalidGraph, e.From, e.To)
+		}
+		if e.From == e.To {
+			return GraphData{}, fmt.Errorf("%w: self-loop on node %d is not allowed", ErrInvalidGraph, e.From)
+		}
+		if math.IsInf(e.Weight, 0) || math.IsNaN(e.Weight) {
+			return GraphData{}, fmt.Errorf("%w: edge %d-%d has invalid weight %g", ErrInvalidGraph, e.From, e.To, e.Weight)
+		}
+		// 無向の負の辺はそれだけで負の閉路 (u→v→u) になる
+		if e.Weight < 0 && !e.Directed {
+			return GraphData{}, fmt.Errorf("%w: undirected edge %d-%d has negative weight %g (only directed edges may be negative)", ErrInvalidGraph, e.From, e.To, e.Weight)
+		}
+		if e.RawDist < 0 {
+			return GraphData{}, fmt.Errorf("%w: edge %d-%d has negative rawDist %g", ErrInvalidGraph, e.From, e.To, e.RawDist)
+		}
+		if e.Capacity < 0 {
+			return GraphData{}, fmt.Errorf("%w: edge %d-%d has negative capacity %g", ErrInvalidGraph, e.From, e.To, e.Capacity)
+		}
+		if e.Risk < 0 || math.IsInf(e.Risk, 0) || math.IsNaN(e.Risk) {
+			return GraphData{}, fmt.Errorf("%w: edge %d-%d has invalid risk %g (must be finite and >= 0)", ErrInvalidGraph, e.From, e.To, e.Risk)
+		}
+		forward, backward := [2]int{e.From, e.To}, [2]int{e.To, e.From}
+		if linked[forward] || (!e.Directed && linked[backward]) {
+			continue
+		}
+		linked[forward] = true
+		if !e.Directed {
+			linked[backward] = true
+		}
+
+		// 実距離の省略時は座標上の長さ (Config.Metric) を使う
+		if e.RawDist == 0 {
+			e.RawDist = space.distance(nodes[e.From], nodes[e.To])
+		}
+		edges = append(edges, e)
+	}
+
+	for _, o := range graph.Obstacles {
+		if err := o.validate(); err != nil {
+			return GraphData{}, fmt.Errorf("%w: %v", ErrInvalidGraph, err)
+		}
+	}
+
+	normalized := GraphData{Nodes: nodes, Edges: edges, Obstacles: obstaclePolygons(graph.Obstacles), Is3D: hasDepth(nodes), Torus: graph.Torus}
+	if cfg.ConnectComponents {
+		connectComponents(&normalized, space)
+	}
+	fillWeights(normalized, cfg)
+	return normalized, nil
+}
+
+// fillWeights: 重みが 0 の辺に RawDist / 正規化の除数 を設定し、正の重みを MinWeight 以上にする
+// 負の重みはそのまま残す
+func fillWeights(graph GraphData, cfg Config) {
+	divisor := normalizationDivisor(graph.Nodes, cfg)
+	for i := range graph.Edges {
+		e := &graph.Edges[i]
+		if e.Weight < 0 {
+			continue
+		}
+		if e.Weight == 0 {
+			e.Weight = e.RawDist / divisor
+		}
+		// 重みが0になりすぎると計算(1/dist)でバグるので極小値を保証
+		if e.Weight < MinWeight {
+			e.Weight = MinWeight
+		}
+	}
+}
+
+// normalizationDivisor: 座標上の長さを重みに換算するときの除数
+// extent はノードの外接直方体 (2次元なら矩形) の対角線 (全ノードが同じ座標なら 1)。
+// 対角線は Config.Metric で測る (トーラスでも折り返さない)
+func normalizationDivisor(nodes []Node, cfg Config) float64 {
+	metric := newSpace(false, cfg)
+	switch cfg.Normalization {
+	case NormalizeNone:
+		return 1
+	case NormalizeSpace:
+		return metric.distance(Node{}, Node{X: cfg.Width, Y: cfg.Height, Z: cfg.Depth})
+	}
+
+	if len(nodes) == 0 {
+		return 1
+	}
+	lo, hi := nodes[0], nodes[0]
+	for _, node := range nodes {
+		lo.X, hi.X = math.Min(lo.X, node.X), math.Max(hi.X, node.X)
+		lo.Y, hi.Y = math.Min(lo.Y, node.Y), math.Max(hi.Y, node.Y)
+		lo.Z, hi.Z = math.Min(lo.Z, node.Z), math.Max(hi.Z, node.Z)
+	}
+	if diagonal := metric.distance(lo, hi); diagonal > 0 {
+		return diagonal
+	}
+	return 1
+}
+
+// checkNode: ノードIDが範囲内か確認する
+func (aco *ACO) checkNode(id int) error {
+	if id < 0 || id >= len(aco.Graph.Nodes) {
+		return fmt.Errorf("%w: node %d out of range [0, %d)", ErrInvalidNode, id, len(aco.Graph.Nodes))
+	}
+	return nil
+}
+
+// edgeIndex: Graph.Edges 内で u→v を通れる辺の位置 (なければ -1)
+// 一方通行の辺は向きも一致する必要がある
+func (aco *ACO) edgeIndex(u, v int) int {
+	for i, e := range aco.Graph.Edges {
+		if (e.From == u && e.To == v) || (!e.Directed && e.From == v && e.To == u) {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddEdge: 実行中のグラフに辺を追加する (directed なら u→v の一方通行)
+// weight <= 0 の場合は座標上の長さを既存の辺と同じ縮尺で重みにする
+func (aco *ACO) AddEdge(u, v int, weight float64, directed bool) error {
+	weight, err := aco.addEdge(u, v, weight, directed)
+	if err != nil {
+		return err
+	}
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.AddEdge(u, v, weight, directed)
+	})
+}
+
+// addEdge: AddEdge の本体 (コロニーには伝えない)。実際に使った重みを返す
+func (aco *ACO) addEdge(u, v int, weight float64, directed bool) (float64, error) {
+	if err := aco.checkNode(u); err != nil {
+		return 0, err
+	}
+	if err := aco.checkNode(v); err != nil {
+		return 0, err
+	}
+	if u == v {
+		return 0, fmt.Errorf("%w: self-loop on node %d is not allowed", ErrInvalidGraph, u)
+	}
+	if aco.hasEdge(u, v) || (!directed && aco.hasEdge(v, u)) {
+		return 0, fmt.Errorf("%w: edge %d-%d already exists", ErrInvalidGraph, u, v)
+	}
+
+	rawDist := aco.space().distance(aco.Graph.Nodes[u], aco.Graph.Nodes[v])
+	if weight <= 0 {
+		scale := aco.weightPerUnitLength()
+		if scale <= 0 { // 辺がない、または負の辺がある
+			scale = 1 / normalizationDivisor(aco.Graph.Nodes, aco.Config)
+		}
+		weight = rawDist * scale
+	}
+	if weight < MinWeight {
+		weight = MinWeight
+	}
+
+	e := Edge{From: u, To: v, Weight: weight, RawDist: rawDist, Directed: directed}
+	aco.link(e)
+	aco.Graph.Edges = append(aco.Graph.Edges, e)
+	aco.recordChange(ChangeAddEdge, nil, &e)
+	return weight, nil
+}
+
+// RemoveEdge: 実行中のグラフから辺を取り除く (通行止め)
+// ベスト経路がこの辺を通っていた場合はベストを破棄して再探索させる
+func (aco *ACO) RemoveEdge(u, v int) error {
+	if err := aco.checkNode(u); err != nil {
+		return err
+	}
+	if err := aco.checkNode(v); err != nil {
+		return err
+	}
+	i := aco.edgeIndex(u, v)
+	if i == -1 {
+		return fmt.Errorf("%w: edge %d-%d does not exist", ErrInvalidGraph, u, v)
+	}
+
+	e := aco.Graph.Edges[i]
+	aco.Graph.Edges = append(aco.Graph.Edges[:i], aco.Graph.Edges[i+1:]...)
+	aco.unlink(e.From, e.To)
+	aco.recordChange(ChangeRemoveEdge, nil, &e)
+
+	closed := aco.Config.Mode == ModeTSP
+	if pathUsesEdge(aco.BestPath, e, closed) {
+		aco.clearBest()
+	}
+	aco.filterTopPaths(func(path []int) bool { return !pathUsesEdge(path, e, closed) })
+	aco.filterPareto(func(path []int) bool { return !pathUsesEdge(path, e, closed) })
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.RemoveEdge(u, v)
+	})
+}
+
+// pathUsesEdge: 経路が辺 e を通るか (closed なら最後から最初に戻る辺も含む)
+// 一方通行の辺は From→To の向きで通った場合だけ数える
+func pathUsesEdge(path []int, e Edge, closed bool) bool {
+	uses := func(a, b int) bool {
+		if e.Directed {
+			return a == e.From && b == e.To
+		}
+		return edgeKey(a, b) == edgeKey(e.From, e.To)
+	}
+	for i := 0; i < len(path)-1; i++ {
+		if uses(path[i], path[i+1]) {
+			return true
+		}
+	}
+	return closed && len(path) > 1 && uses(path[len(path)-1], path[0])
+}
+
+// SetNodeCost: ノードの通過コストを変更する (このノードに入る全ての半辺の距離が変わる)
+// ベスト経路と上位経路の距離は新しいコストで計算し直す
+func (aco *ACO) SetNodeCost(id int, cost float64) error {
+	if err := aco.checkNode(id); err != nil {
+		return err
+	}
+	if cost < 0 || math.IsInf(cost, 0) || math.IsNaN(cost) {
+		return fmt.Errorf("%w: node cost must be finite and >= 0 (got %g)", ErrInvalidGraph, cost)
+	}
+
+	aco.Graph.Nodes[id].Cost = cost
+	aco.recordChange(ChangeUpdateNode, &aco.Graph.Nodes[id], nil)
+	for _, e := range aco.Graph.Edges {
+		if e.From == id || e.To == id {
+			aco.setDistance(e)
+		}
+	}
+
+	if aco.BestPath != nil {
+		aco.BestDist = aco.calculatePathDistance(aco.BestPath)
+	}
+	aco.rescoreTopPaths()
+	aco.rescorePareto()
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.SetNodeCost(id, cost)
+	})
+}
+
+// AddNode: 座標 (x, y) にノードを追加し、connectTo の各ノードと接続する
+// 新しいノードのIDを返す
+func (aco *ACO) AddNode(x, y float64, connectTo []int) (int, error) {
+	for _, v := range connectTo {
+		if err := aco.checkNode(v); err != nil {
+			return -1, err
+		}
+	}
+
+	id := len(aco.Graph.Nodes)
+	aco.Graph.Nodes = append(aco.Graph.Nodes, Node{ID: id, X: x, Y: y})
+	aco.recordChange(ChangeAddNode, &aco.Graph.Nodes[id], nil)
+
+	aco.Adj = append(aco.Adj, nil)
+
+	for _, v := range connectTo {
+		if v == id || aco.hasEdge(id, v) {
+			continue
+		}
+		if _, err := aco.addEdge(id, v, 0, false); err != nil {
+			return -1, err
+		}
+	}
+
+	// TSPでは既存の巡回が全ノードを回らなくなる
+	if aco.Config.Mode == ModeTSP {
+		aco.clearBest()
+	}
+	return id, aco.forEachColony(func(colony *ACO) error {
+		_, err := colony.AddNode(x, y, connectTo)
+		return err
+	})
+}
+
+// RemoveNode: ノードと接続する辺を削除し、後ろのノードIDを1つずつ詰める
+// スタート・ゴール (複数ゴールを含む)・経由地は削除できない
+func (aco *ACO) RemoveNode(id int) error {
+	if err := aco.checkNode(id); err != nil {
+		return err
+	}
+	if id == aco.StartNode || aco.isGoal(id) || aco.isWaypoint(id) {
+		return fmt.Errorf("%w: cannot remove start, goal or waypoint node %d", ErrInvalidNode, id)
+	}
+	if len(aco.Graph.Nodes) <= 2 {
+		return fmt.Errorf("%w: graph needs at least 2 nodes", ErrInvalidGraph)
+	}
+
+	remap := func(v int) int {
+		if v > id {
+			return v - 1
+		}
+		return v
+	}
+
+	nodes := make([]Node, 0, len(aco.Graph.Nodes)-1)
+	for _, node := range aco.Graph.Nodes {
+		if node.ID == id {
+			continue
+		}
+		node.ID = remap(node.ID)
+		nodes = append(nodes, node)
+	}
+	edges := make([]Edge, 0, len(aco.Graph.Edges))
+	for _, e := range aco.Graph.Edges {
+		if e.From == id || e.To == id {
+			continue
+		}
+		e.From, e.To = remap(e.From), remap(e.To)
+		edges = append(edges, e)
+	}
+
+	// 隣接リストから id を取り除き、行き先を付け替える
+	adj := make([][]Neighbor, 0, len(aco.Adj)-1)
+	for u, neighbors := range aco.Adj {
+		if u == id {
+			continue
+		}
+		kept := neighbors[:0]
+		for _, nb := range neighbors {
+			if nb.To == id {
+				continue
+			}
+			nb.To = remap(nb.To)
+			kept = append(kept, nb)
+		}
+		adj = append(adj, kept)
+	}
+
+	aco.recordChange(ChangeRemoveNode, &aco.Graph.Nodes[id], nil)
+	aco.Graph.Nodes = nodes
+	aco.Graph.Edges = edges
+	aco.Adj = adj
+	aco.StartNode = remap(aco.StartNode)
+	aco.GoalNode = remap(aco.GoalNode)
+	for i, g := range aco.Goals {
+		aco.Goals[i] = remap(g)
+	}
+	for i, w := range aco.Waypoints {
+		aco.Waypoints[i] = remap(w)
+	}
+	aco.removeVisits(id)
+
+	// ベスト経路が削除ノードを通っていれば破棄、そうでなければIDを付け替える
+	usesNode := aco.Config.Mode == ModeTSP
+	for _, v := range aco.BestPath {
+		if v == id {
+			usesNode = true
+		}
+	}
+	if usesNode {
+		aco.clearBest()
+	} else {
+		for i, v := range aco.BestPath {
+			aco.BestPath[i] = remap(v)
+		}
+	}
+	aco.filterTopPaths(func(path []int) bool {
+		for _, v := range path {
+			if v == id {
+				return false
+			}
+		}
+		return true
+	})
+	for _, ranked := range aco.TopPaths {
+		for i, v := range ranked.Path {
+			ranked.Path[i] = remap(v)
+		}
+	}
+	aco.filterPareto(func(path []int) bool { return !slices.Contains(path, id) })
+	for _, p := range aco.ParetoFront {
+		for i, v := range p.Path {
+			p.Path[i] = remap(v)
+		}
+	}
+	aco.History = slices.DeleteFunc(aco.History, func(h Improvement) bool {
+		return slices.Contains(h.Path, id)
+	})
+	for _, h := range aco.History {
+		for i, v := range h.Path {
+			h.Path[i] = remap(v)
+		}
+	}
+	aco.remapReplay(id, remap)
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.RemoveNode(id)
+	})
+}
+
+// SetEdgeWeight: 既存の辺の重みを変更する (渋滞などの再現用)
+// 次の Step からヒューリスティック 1/dist に反映される。一方通行の辺には負の重みも設定できる
+func (aco *ACO) SetEdgeWeight(u, v int, weight float64) error {
+	if err := aco.checkNode(u); err != nil {
+		return err
+	}
+	if err := aco.checkNode(v); err != nil {
+		return err
+	}
+	i := aco.edgeIndex(u, v)
+	if i == -1 {
+		return fmt.Errorf("%w: edge %d-%d does not exist", ErrInvalidGraph, u, v)
+	}
+	if weight == 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return fmt.Errorf("%w: weight must be finite and non-zero (got %g)", ErrInvalidGraph, weight)
+	}
+	if weight < 0 && !aco.Graph.Edges[i].Directed {
+		return fmt.Errorf("%w: undirected edge %d-%d cannot have negative weight %g (only directed edges may be negative)", ErrInvalidGraph, u, v, weight)
+	}
+	if weight > 0 && weight < MinWeight {
+		weight = MinWeight
+	}
+
+	aco.Graph.Edges[i].Weight = weight
+	e := aco.Graph.Edges[i]
+	aco.setDistance(e)
+	aco.recordChange(ChangeUpdateEdge, nil, &e)
+
+	// ベスト経路の距離は古い重みで計算されているので再評価する
+	if pathUsesEdge(aco.BestPath, e, aco.Config.Mode == ModeTSP) {
+		aco.BestDist = aco.calculatePathDistance(aco.BestPath)
+	}
+	aco.rescoreTopPaths()
+	aco.rescorePareto()
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.SetEdgeWeight(u, v, weight)
+	})
+}
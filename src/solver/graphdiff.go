@@ -0,0 +1,73 @@
+package solver
+
+// グラフの差分 (動的な編集のたびにグラフ全体を取り直さないためのもの)
+// AddNode・RemoveNode・AddEdge・RemoveEdge・SetNodeCost・SetEdgeWeight は1件の変更ごとに
+// Graph.Version を1つ進めて履歴に残し、GraphDiff は指定した版より後の変更を順に返す。
+// AddNode の接続は、ノードの追加に続く辺の追加として記録する。
+// 履歴は直近の GraphChangeLimit 件だけ持ち、それより古い版からの差分は Full (取り直し) を返す。
+
+// GraphChangeLimit: 保持する変更の最大件数
+const GraphChangeLimit = 1000
+
+// 変更の種類 (GraphChange.Op)
+const (
+	ChangeAddNode    = "addNode"
+	ChangeRemoveNode = "removeNode" // 接続する辺も消え、後ろのノードIDは1つずつ詰まる
+	ChangeUpdateNode = "updateNode"
+	ChangeAddEdge    = "addEdge" // Graph.Edges の末尾に加わる
+	ChangeRemoveEdge = "removeEdge"
+	ChangeUpdateEdge = "updateEdge"
+)
+
+// GraphChange: 1件の変更 (Node・Edge は変更後の値、削除では削除したもの)
+type GraphChange struct {
+	Version int    `json:"version"`
+	Op      string `json:"op"`
+	Node    *Node  `json:"node,omitempty"`
+	Edge    *Edge  `json:"edge,omitempty"`
+}
+
+// GraphDiff: GraphDiff の結果
+type GraphDiff struct {
+	Version int `json:"version"` // 現在の版
+	// 履歴が足りず差分を返せない (受け取り側はグラフ全体を取り直す)
+	Full    bool          `json:"full"`
+	Changes []GraphChange `json:"changes"`
+}
+
+// recordChange: 版を進めて変更を履歴に残す (node・edge は複製する)
+func (aco *ACO) recordChange(op string, node *Node, edge *Edge) {
+	aco.Graph.Version++
+	change := GraphChange{Version: aco.Graph.Version, Op: op}
+	if node != nil {
+		copied := *node
+		change.Node = &copied
+	}
+	if edge != nil {
+		copied := *edge
+		change.Edge = &copied
+	}
+	aco.graphLog = append(aco.graphLog, change)
+	if len(aco.graphLog) > GraphChangeLimit {
+		aco.graphLog = append([]GraphChange(nil), aco.graphLog[len(aco.graphLog)-GraphChangeLimit:]...)
+	}
+}
+
+// GraphDiff: 版 since より後の変更を返す
+// since が現在の版より新しい (別のインスタンスの版など) か、履歴から消えた版なら Full
+func (aco *ACO) GraphDiff(since int) GraphDiff {
+	diff := GraphDiff{Version: aco.Graph.Version, Changes: []GraphChange{}}
+	if since == aco.Graph.Version {
+		return diff
+	}
+	if since < 0 || since > aco.Graph.Version || len(aco.graphLog) == 0 || since < aco.graphLog[0].Version-1 {
+		diff.Full = true
+		return diff
+	}
+	for _, change := range aco.graphLog {
+		if change.Version > since {
+			diff.Changes = append(diff.Changes, change)
+		}
+	}
+	return diff
+}
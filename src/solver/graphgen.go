@@ -0,0 +1,394 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ネットワーク構造 (Config.Topology)
+const (
+	TopologyRing           = "ring"            // 連結リング + ランダムなショートカット
+	TopologyGrid           = "grid"            // 格子
+	TopologyDelaunay       = "delaunay"        // ランダム点のドロネー三角形分割
+	TopologyWattsStrogatz  = "watts-strogatz"  // スモールワールド
+	TopologyBarabasiAlbert = "barabasi-albert" // スケールフリー
+)
+
+const (
+	wattsStrogatzNeighbors = 2   // 片側に繋ぐ近傍数 k
+	wattsStrogatzRewire    = 0.1 // 張り替え確率 p
+	barabasiAlbertLinks    = 2   // 新ノードが張る辺の数 m
+)
+
+// graphGenerators: トポロジー名 → 生成関数
+var graphGenerators = map[string]func(nodeCount int, cfg Config, randSource *rand.Rand) GraphData{
+	TopologyRing:           generateRing,
+	TopologyGrid:           generateGrid,
+	TopologyDelaunay:       generateDelaunay,
+	TopologyWattsStrogatz:  generateWattsStrogatz,
+	TopologyBarabasiAlbert: generateBarabasiAlbert,
+}
+
+// TopologyNames: 生成できるトポロジー名 (ソート済み)
+func TopologyNames() []string {
+	names := make([]string, 0, len(graphGenerators))
+	for name := range graphGenerators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateTopology(topology string) error {
+	if _, ok := graphGenerators[topology]; !ok {
+		return fmt.Errorf("%w: unknown topology %q", ErrInvalidConfig, topology)
+	}
+	return nil
+}
+
+func generateGraph(nodeCount int, cfg Config, randSource *rand.Rand) GraphData {
+	generate, ok := graphGenerators[cfg.Topology]
+	if !ok {
+		generate = generateRing
+	}
+	graph := generate(nodeCount, cfg, randSource)
+	graph.Obstacles = obstaclePolygons(cfg.Obstacles)
+	graph.Is3D = cfg.Depth > 0
+	graph.Torus = cfg.Torus
+	if cfg.OneWayRatio > 0 {
+		makeOneWay(graph.Edges, cfg.OneWayRatio, randSource)
+	}
+	if cfg.ConnectComponents {
+		connectComponents(&graph, newSpace(cfg.Torus, cfg)) // つなぐ辺は一方通行にしない
+	}
+	fillWeights(graph, cfg)
+	if cfg.Mode == ModeOrienteering {
+		assignPrizes(graph.Nodes, randSource)
+	}
+	return graph
+}
+
+// makeOneWay: 辺を ratio の確率で一方通行にする (向きは半々でランダム)
+func makeOneWay(edges []Edge, ratio float64, randSource *rand.Rand) {
+	for i := range edges {
+		if randSource.Float64() >= ratio {
+			continue
+		}
+		edges[i].Directed = true
+		if randSource.Intn(2) == 0 {
+			edges[i].From, edges[i].To = edges[i].To, edges[i].From
+		}
+	}
+}
+
+// targetEdgeCount: averageDegree / density から目標の辺数を求める (0 はトポロジー既定)
+// averageDegree が優先され、結果は完全グラフの辺数で頭打ちになる
+func targetEdgeCount(nodeCount int, cfg Config) int {
+	maxEdges := nodeCount * (nodeCount - 1) / 2
+	target := 0
+	switch {
+	case cfg.AverageDegree > 0:
+		target = int(math.Round(cfg.AverageDegree * float64(nodeCount) / 2))
+	case cfg.Density > 0:
+		target = int(math.Round(cfg.Density * float64(maxEdges)))
+	}
+	if target > maxEdges {
+		target = maxEdges
+	}
+	return target
+}
+
+// linksPerNode: 目標辺数をノードあたりの辺数 (WSのk, BAのm) に換算する
+func linksPerNode(nodeCount int, cfg Config, fallback int) int {
+	target := targetEdgeCount(nodeCount, cfg)
+	if target == 0 {
+		return fallback
+	}
+	links := int(math.Round(float64(target) / float64(nodeCount)))
+	if links < 1 {
+		links = 1
+	}
+	return links
+}
+
+// graphBuilder: 重複を除きつつ実距離付きの辺を追加する (重みは generateGraph で正規化する)
+// 障害物を横切る辺は張らない
+type graphBuilder struct {
+	nodes     []Node
+	edges     []Edge
+	linked    map[[2]int]bool
+	obstacles []Obstacle
+	space     space
+}
+
+func newGraphBuilder(nodes []Node, cfg Config) *graphBuilder {
+	return &graphBuilder{nodes: nodes, edges: []Edge{}, linked: make(map[[2]int]bool), obstacles: cfg.Obstacles, space: newSpace(cfg.Torus, cfg)}
+}
+
+// randomNodes: Width x Height (Depth > 0 なら x Depth) の範囲にランダムに配置したノード
+// 障害物の内側に落ちた点は obstacleRetries 回まで引き直す
+func randomNodes(nodeCount int, cfg Config, randSource *rand.Rand) []Node {
+	nodes := make([]Node, nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		x, y := randSource.Float64()*cfg.Width, randSource.Float64()*cfg.Height
+		for retry := 0; retry < obstacleRetries && insideObstacle(cfg.Obstacles, x, y); retry++ {
+			x, y = randSource.Float64()*cfg.Width, randSource.Float64()*cfg.Height
+		}
+		nodes[i] = Node{ID: i, X: x, Y: y}
+		if cfg.Depth > 0 {
+			nodes[i].Z = randSource.Float64() * cfg.Depth
+		}
+	}
+	return nodes
+}
+
+func (b *graphBuilder) hasEdge(u, v int) bool {
+	return b.linked[edgeKey(u, v)]
+}
+
+func (b *graphBuilder) addEdge(u, v int) {
+	if u == v || b.hasEdge(u, v) || blockedByObstacle(b.obstacles, b.nodes[u], b.nodes[v]) {
+		return
+	}
+	b.linked[edgeKey(u, v)] = true
+
+	// 座標上の長さを Config.Metric で測る (トーラスでは折り返した短い方)
+	rawDist := b.space.distance(b.nodes[u], b.nodes[v])
+	b.edges = append(b.edges, Edge{From: u, To: v, RawDist: rawDist})
+}
+
+func (b *graphBuilder) graph() GraphData {
+	return GraphData{Nodes: b.nodes, Edges: b.edges}
+}
+
+// generateRing: 連結リング + ランダムなショートカット
+// 目標辺数があればそれに達するまで、なければ nodeCount*3 回ショートカットを試みる
+func generateRing(nodeCount int, cfg Config, randSource *rand.Rand) GraphData {
+	b := newGraphBuilder(randomNodes(nodeCount, cfg, randSource), cfg)
+
+	// グラフ生成（連結リング）
+	for i := 0; i < nodeCount; i++ {
+		b.addEdge(i, (i+1)%nodeCount)
+	}
+	// ショートカット生成
+	if target := targetEdgeCount(nodeCount, cfg); target > 0 {
+		// 密なグラフでは重複が増えるので試行回数に余裕を持たせる
+		for attempt := 0; len(b.edges) < target && attempt < target*20; attempt++ {
+			b.addEdge(randSource.Intn(nodeCount), randSource.Intn(nodeCount))
+		}
+		return b.graph()
+	}
+	extraEdges := nodeCount * 3
+	for i := 0; i < extraEdges; i++ {
+		b.addEdge(randSource.Intn(nodeCount), randSource.Intn(nodeCount))
+	}
+
+	return b.graph()
+}
+
+// generateGrid: ほぼ正方形の格子 (ノード0が左上、n-1が右下寄り)
+// Depth > 0 ならほぼ立方体の格子を層ごとに Z 方向へ重ねる (ノード0が手前の層)
+// トーラスでは端を反対側の端と結び、折り返す辺も同じ長さになるよう均等に並べる
+// 構造が固定なので averageDegree / density は無視する
+func generateGrid(nodeCount int, cfg Config, randSource *rand.Rand) GraphData {
+	cols := int(math.Ceil(math.Sqrt(float64(nodeCount))))
+	rows := (nodeCount + cols - 1) / cols
+	layers := 1
+	if cfg.Depth > 0 {
+		cols = int(math.Round(math.Cbrt(float64(nodeCount))))
+		if cols*cols*cols < nodeCount {
+			cols++
+		}
+		rows = cols
+		layers = (nodeCount + cols*rows - 1) / (cols * rows)
+	}
+	layerSize := cols * rows
+	// 各軸の間隔と端の余白
+	axis := func(size float64, count int) (float64, float64) {
+		if cfg.Torus {
+			return size / float64(count), size / float64(count) / 2
+		}
+		return size * 0.9 / math.Max(1, float64(count-1)), size * 0.05
+	}
+	spacingX, marginX := axis(cfg.Width, cols)
+	spacingY, marginY := axis(cfg.Height, rows)
+	spacingZ, marginZ := axis(cfg.Depth, layers)
+
+	nodes := make([]Node, nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		j := i % layerSize // 層の中の位置
+		nodes[i] = Node{
+			ID: i,
+			X:  marginX + float64(j%cols)*spacingX,
+			Y:  marginY + float64(j/cols)*spacingY,
+			Z:  marginZ + float64(i/layerSize)*spacingZ,
+		}
+	}
+
+	b := newGraphBuilder(nodes, cfg)
+	for i := 0; i < nodeCount; i++ {
+		if (i+1)%cols != 0 && i+1 < nodeCount {
+			b.addEdge(i, i+1) // 右
+		}
+		if i%layerSize+cols < layerSize && i+cols < nodeCount {
+			b.addEdge(i, i+cols) // 下
+		}
+		if i+layerSize < nodeCount {
+			b.addEdge(i, i+layerSize) // 奥 (3次元のみ)
+		}
+		if cfg.Torus {
+			// 右端・下端・最奥の層から反対側へ折り返す
+			j := i % layerSize
+			if j%cols == cols-1 {
+				b.addEdge(i, i-(cols-1))
+			}
+			if j/cols == rows-1 {
+				b.addEdge(i, i-(rows-1)*cols)
+			}
+			if i/layerSize == layers-1 {
+				b.addEdge(i, i%layerSize)
+			}
+		}
+	}
+
+	return b.graph()
+}
+
+// generateDelaunay: ランダム点をドロネー三角形分割 (Bowyer-Watson法)
+// 構造が固定なので averageDegree / density は無視する
+func generateDelaunay(nodeCount int, cfg Config, randSource *rand.Rand) GraphData {
+	nodes := randomNodes(nodeCount, cfg, randSource)
+	b := newGraphBuilder(nodes, cfg)
+
+	type triangle struct {
+		a, b, c    int
+		cx, cy, r2 float64 // 外接円
+	}
+
+	// 全ての点を含む巨大な三角形 (super triangle) の頂点を末尾に追加
+	px := make([]float64, nodeCount, nodeCount+3)
+	py := make([]float64, nodeCount, nodeCount+3)
+	for i, node := range nodes {
+		px[i], py[i] = node.X, node.Y
+	}
+	size := 100 * math.Max(cfg.Width, cfg.Height)
+	px = append(px, cfg.Width/2, -size, size)
+	py = append(py, -size, size, size)
+
+	newTriangle := func(i, j, k int) triangle {
+		cx, cy, r2 := circumcircle(px[i], py[i], px[j], py[j], px[k], py[k])
+		return triangle{a: i, b: j, c: k, cx: cx, cy: cy, r2: r2}
+	}
+
+	triangles := []triangle{newTriangle(nodeCount, nodeCount+1, nodeCount+2)}
+	for p := 0; p < nodeCount; p++ {
+		// 外接円が点pを含む三角形を取り除き、その穴の境界を点pと結ぶ
+		boundary := make(map[[2]int]int)
+		kept := triangles[:0:0]
+		for _, t := range triangles {
+			if (px[p]-t.cx)*(px[p]-t.cx)+(py[p]-t.cy)*(py[p]-t.cy) <= t.r2 {
+				boundary[edgeKey(t.a, t.b)]++
+				boundary[edgeKey(t.b, t.c)]++
+				boundary[edgeKey(t.c, t.a)]++
+			} else {
+				kept = append(kept, t)
+			}
+		}
+		for e, count := range boundary {
+			if count == 1 {
+				kept = append(kept, newTriangle(e[0], e[1], p))
+			}
+		}
+		triangles = kept
+	}
+
+	for _, t := range triangles {
+		for _, e := range [][2]int{{t.a, t.b}, {t.b, t.c}, {t.c, t.a}} {
+			if e[0] < nodeCount && e[1] < nodeCount {
+				b.addEdge(e[0], e[1])
+			}
+		}
+	}
+
+	return b.graph()
+}
+
+// circumcircle: 三角形の外接円 (中心と半径の2乗)。退化した三角形は全平面を覆う円とみなす
+func circumcircle(ax, ay, bx, by, cx, cy float64) (float64, float64, float64) {
+	d := 2 * (ax*(by-cy) + bx*(cy-ay) + cx*(ay-by))
+	if d == 0 {
+		return 0, 0, math.Inf(1)
+	}
+	a2, b2, c2 := ax*ax+ay*ay, bx*bx+by*by, cx*cx+cy*cy
+	ux := (a2*(by-cy) + b2*(cy-ay) + c2*(ay-by)) / d
+	uy := (a2*(cx-bx) + b2*(ax-cx) + c2*(bx-ax)) / d
+	return ux, uy, (ax-ux)*(ax-ux) + (ay-uy)*(ay-uy)
+}
+
+// generateWattsStrogatz: 円周上のリング格子 (各ノードが片側k近傍と接続) の辺を確率pで張り替える
+// 3次元でも円は Z 方向の中央の平面に置く
+func generateWattsStrogatz(nodeCount int, cfg Config, randSource *rand.Rand) GraphData {
+	nodes := make([]Node, nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(nodeCount)
+		nodes[i] = Node{ID: i, X: cfg.Width * (0.5 + 0.45*math.Cos(angle)), Y: cfg.Height * (0.5 + 0.45*math.Sin(angle)), Z: cfg.Depth / 2}
+	}
+	b := newGraphBuilder(nodes, cfg)
+	neighbors := linksPerNode(nodeCount, cfg, wattsStrogatzNeighbors)
+
+	for i := 0; i < nodeCount; i++ {
+		for k := 1; k <= neighbors; k++ {
+			j := (i + k) % nodeCount
+			if randSource.Float64() < wattsStrogatzRewire {
+				// 張り替え先は自己ループ・既存辺以外からランダムに選ぶ (見つからなければ元の辺)
+				for attempt := 0; attempt < nodeCount; attempt++ {
+					candidate := randSource.Intn(nodeCount)
+					if candidate != i && !b.hasEdge(i, candidate) {
+						j = candidate
+						break
+					}
+				}
+			}
+			b.addEdge(i, j)
+		}
+	}
+
+	return b.graph()
+}
+
+// generateBarabasiAlbert: 優先的選択によるスケールフリーネットワーク
+// 最初の m+1 ノードは完全グラフ、以降は次数に比例した確率で m 本の辺を張る
+func generateBarabasiAlbert(nodeCount int, cfg Config, randSource *rand.Rand) GraphData {
+	b := newGraphBuilder(randomNodes(nodeCount, cfg, randSource), cfg)
+	m := linksPerNode(nodeCount, cfg, barabasiAlbertLinks)
+
+	// 次数に比例して選ぶため、辺の端点を列挙したリストからサンプリングする
+	endpoints := []int{}
+	seedCount := m + 1
+	if seedCount > nodeCount {
+		seedCount = nodeCount
+	}
+	for i := 0; i < seedCount; i++ {
+		for j := i + 1; j < seedCount; j++ {
+			b.addEdge(i, j)
+			endpoints = append(endpoints, i, j)
+		}
+	}
+
+	for i := seedCount; i < nodeCount; i++ {
+		targets := map[int]bool{}
+		for len(targets) < m && len(targets) < i {
+			targets[endpoints[randSource.Intn(len(endpoints))]] = true
+		}
+		for t := 0; t < i; t++ {
+			if targets[t] {
+				b.addEdge(i, t)
+				endpoints = append(endpoints, i, t)
+			}
+		}
+	}
+
+	return b.graph()
+}
@@ -0,0 +1,155 @@
+package solver
+
+// グラフの構造指標 (可視化の横に表示する)
+// 辺の向きは無視し、互いに逆向きの一方通行の辺は1本の隣接として数える。
+// 直径・半径は辺の本数 (ホップ数) で測り、連結でなければ各ノードの離心率は同じ成分の中だけで求める。
+
+// MetricsExactLimit: これ以下のノード数なら全ノードから幅優先探索して離心率を厳密に求める
+// (超える場合は MetricsSamples 個のノードからの探索で推定する)
+const (
+	MetricsExactLimit = 500
+	MetricsSamples    = 32
+)
+
+// GraphMetrics: GraphMetrics の結果
+type GraphMetrics struct {
+	NodeCount     int     `json:"nodeCount"`
+	EdgeCount     int     `json:"edgeCount"`
+	AverageDegree float64 `json:"averageDegree"`
+	// DegreeDistribution[k] は次数 k のノードの数
+	DegreeDistribution []int `json:"degreeDistribution"`
+	// 離心率の最大 (直径) と最小 (半径)。Exact でなければ標本から求めた推定値
+	// (直径は下界、半径は上界)
+	Diameter int  `json:"diameter"`
+	Radius   int  `json:"radius"`
+	Exact    bool `json:"exact"`
+	// 平均クラスタ係数 (次数 1 以下のノードは 0 として平均する)
+	Clustering float64 `json:"clustering"`
+	Components int     `json:"components"`
+}
+
+// GraphMetrics: 現在のグラフの構造指標を求める
+func (aco *ACO) GraphMetrics() GraphMetrics {
+	n := len(aco.Graph.Nodes)
+	neighbors := aco.undirectedNeighbors()
+
+	metrics := GraphMetrics{
+		NodeCount:  n,
+		EdgeCount:  len(aco.Graph.Edges),
+		Components: len(aco.components()),
+		Exact:      n <= MetricsExactLimit,
+	}
+	maxDegree, totalDegree := 0, 0
+	for _, nbs := range neighbors {
+		maxDegree = max(maxDegree, len(nbs))
+		totalDegree += len(nbs)
+	}
+	metrics.AverageDegree = float64(totalDegree) / float64(n)
+	metrics.DegreeDistribution = make([]int, maxDegree+1)
+	for _, nbs := range neighbors {
+		metrics.DegreeDistribution[len(nbs)]++
+	}
+
+	metrics.Clustering = clusteringCoefficient(neighbors)
+	metrics.Diameter, metrics.Radius = eccentricityBounds(neighbors, metrics.Exact)
+	return metrics
+}
+
+// undirectedNeighbors: 向きを無視した隣接ノード (重複なし)
+func (aco *ACO) undirectedNeighbors() [][]int {
+	n := len(aco.Graph.Nodes)
+	linked := make(map[[2]int]bool, len(aco.Graph.Edges))
+	neighbors := make([][]int, n)
+	for _, e := range aco.Graph.Edges {
+		key := edgeKey(e.From, e.To)
+		if linked[key] {
+			continue
+		}
+		linked[key] = true
+		neighbors[e.From] = append(neighbors[e.From], e.To)
+		neighbors[e.To] = append(neighbors[e.To], e.From)
+	}
+	return neighbors
+}
+
+// clusteringCoefficient: 各ノードの隣接ノードどうしが結ばれている割合の平均
+func clusteringCoefficient(neighbors [][]int) float64 {
+	n := len(neighbors)
+	mark := make([]int, n) // mark[v] == u+1 なら v は u の隣接ノード
+	total := 0.0
+	for u, nbs := range neighbors {
+		k := len(nbs)
+		if k < 2 {
+			continue
+		}
+		for _, v := range nbs {
+			mark[v] = u + 1
+		}
+		links := 0
+		for _, v := range nbs {
+			for _, w := range neighbors[v] {
+				if mark[w] == u+1 {
+					links++
+				}
+			}
+		}
+		// 隣接ノードどうしの辺は両端から1回ずつ数えている
+		total += float64(links) / float64(k*(k-1))
+	}
+	return total / float64(n)
+}
+
+// eccentricityBounds: 直径と半径 (exact でなければ、等間隔に選んだ標本と、最も遠かったノードからの探索で推定する)
+func eccentricityBounds(neighbors [][]int, exact bool) (diameter, radius int) {
+	n := len(neighbors)
+	sources := make([]int, 0, MetricsSamples+1)
+	if exact {
+		for v := 0; v < n; v++ {
+			sources = append(sources, v)
+		}
+	} else {
+		for i := 0; i < MetricsSamples; i++ {
+			sources = append(sources, i*n/MetricsSamples)
+		}
+	}
+
+	radius = -1
+	dist := make([]int, n)
+	farthest := -1 // 標本から最も遠かったノード (その離心率は直径に近いことが多い)
+	eccentricity := func(source int) int {
+		for v := range dist {
+			dist[v] = -1
+		}
+		dist[source] = 0
+		queue := []int{source}
+		ecc, far := 0, source
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range neighbors[u] {
+				if dist[v] == -1 {
+					dist[v] = dist[u] + 1
+					queue = append(queue, v)
+					if dist[v] > ecc {
+						ecc, far = dist[v], v
+					}
+				}
+			}
+		}
+		if ecc > diameter || farthest == -1 {
+			farthest = far
+		}
+		return ecc
+	}
+	for _, source := range sources {
+		ecc := eccentricity(source)
+		diameter = max(diameter, ecc)
+		if radius == -1 || ecc < radius {
+			radius = ecc
+		}
+	}
+	if !exact {
+		diameter = max(diameter, eccentricity(farthest))
+	}
+	return diameter, radius
+}
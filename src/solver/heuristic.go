@@ -0,0 +1,81 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// アリのヒューリスティック (辺の望ましさ η。選択確率は τ^α · η^β に比例する)
+// 名前で登録しておき Config.Heuristic で選ぶので、新しい η を試すのに解法側の変更はいらない。
+
+const (
+	HeuristicInverseDistance = "inverse-distance" // 1 / 距離 (従来の η)
+	HeuristicGoalDirected    = "goal-directed"    // 1 / (距離 + 行き先までの直線距離の下界)
+	HeuristicDegree          = "degree"           // (1 + 行き先の出次数) / 距離 (分岐の多いノードを好む)
+)
+
+// HeuristicFunc: from から nb.To へ進む望ましさ (0 以上。0 なら選ばれない)
+// targets は現在の区間の行き先 (経由地、または最後の区間のゴール。TSP では nil)
+type HeuristicFunc func(from int, nb Neighbor, targets []int) float64
+
+// Heuristic: イテレーションの始めにインスタンスから HeuristicFunc を作る
+// (グラフ全体から求める定数はここで1度だけ計算する)
+type Heuristic func(aco *ACO) HeuristicFunc
+
+// heuristics: 名前で選択できるヒューリスティック
+var heuristics = map[string]Heuristic{
+	HeuristicInverseDistance: func(*ACO) HeuristicFunc {
+		return func(_ int, nb Neighbor, _ []int) float64 { return 1.0 / nb.Dist }
+	},
+	HeuristicGoalDirected: func(aco *ACO) HeuristicFunc {
+		unit := aco.weightPerUnitLength()
+		space := aco.space()
+		return func(_ int, nb Neighbor, targets []int) float64 {
+			remaining := 0.0
+			if len(targets) > 0 {
+				remaining = math.Inf(1)
+				to := aco.Graph.Nodes[nb.To]
+				for _, t := range targets {
+					remaining = math.Min(remaining, space.distance(to, aco.Graph.Nodes[t])*unit)
+				}
+			}
+			return 1.0 / (nb.Dist + remaining)
+		}
+	},
+	HeuristicDegree: func(aco *ACO) HeuristicFunc {
+		return func(_ int, nb Neighbor, _ []int) float64 {
+			return float64(1+len(aco.Adj[nb.To])) / nb.Dist
+		}
+	},
+}
+
+// builtinHeuristics: RegisterHeuristic で置き換えられない組み込みの名前
+var builtinHeuristics = map[string]bool{
+	HeuristicInverseDistance: true,
+	HeuristicGoalDirected:    true,
+	HeuristicDegree:          true,
+}
+
+// RegisterHeuristic: ヒューリスティックを name で登録する (同じ名前の登録済みのものは置き換える)
+// 組み込みの名前は使えない
+func RegisterHeuristic(name string, h Heuristic) error {
+	if name == "" || h == nil {
+		return fmt.Errorf("%w: heuristic needs a name and a function", ErrInvalidConfig)
+	}
+	if builtinHeuristics[name] {
+		return fmt.Errorf("%w: heuristic %q is built in and cannot be replaced", ErrInvalidConfig, name)
+	}
+	heuristics[name] = h
+	return nil
+}
+
+// HeuristicNames: 登録済みヒューリスティック名 (ソート済み)
+func HeuristicNames() []string {
+	names := make([]string, 0, len(heuristics))
+	for name := range heuristics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,24 @@
+package solver
+
+import "time"
+
+// ベスト経路の改善履歴 (タイムラインでベスト経路の移り変わりを再生するため)
+
+// Improvement: ベスト経路が改善したイテレーション
+type Improvement struct {
+	Iteration int     `json:"iteration"`
+	Dist      float64 `json:"dist"`
+	Path      []int   `json:"path"`
+	// 記録した時刻 (Unix エポックからのミリ秒、JS の Date と同じ)
+	Time int64 `json:"time"`
+}
+
+// recordImprovement: 現在のベスト経路を改善履歴に追加する (Step で改善したときに呼ぶ)
+func (aco *ACO) recordImprovement() {
+	aco.History = append(aco.History, Improvement{
+		Iteration: aco.Iteration,
+		Dist:      aco.BestDist,
+		Path:      append([]int(nil), aco.BestPath...),
+		Time:      time.Now().UnixMilli(),
+	})
+}
@@ -0,0 +1,100 @@
+package solver
+
+// 局所探索 (Config.LocalSearch)
+// ゴールできたアリの経路をフェロモン散布の前に改善する。
+// グラフは疎なので、つなぎ替えに使う辺が実在する場合だけ適用する。
+
+// localSearchEpsilon: 浮動小数誤差で改善を繰り返さないための閾値
+const localSearchEpsilon = 1e-9
+
+// localSearch: TSPモードは 2-opt、経路探索モードはショートカットで path をその場で改善する
+// 2-opt は区間を逆向きにたどり直すので、一方通行の辺があるグラフでは行わない
+func (aco *ACO) localSearch(path []int) []int {
+	if aco.Config.Mode == ModeTSP {
+		if !aco.hasOneWayEdges() {
+			aco.twoOpt(path)
+		}
+		return path
+	}
+	if len(aco.Waypoints) == 0 {
+		return aco.shortcut(path)
+	}
+
+	// 経由地を飛ばさないよう、経由地で区切った区間ごとにショートカットする
+	// (各区間は最初に経由地へ着いた位置で終わる)
+	var improved []int
+	begin, leg := 0, 0
+	for i := 1; i < len(path); i++ {
+		if i < len(path)-1 && (leg >= len(aco.Waypoints) || path[i] != aco.Waypoints[leg]) {
+			continue
+		}
+		segment := aco.shortcut(append([]int(nil), path[begin:i+1]...))
+		if len(improved) > 0 {
+			segment = segment[1:]
+		}
+		improved = append(improved, segment...)
+		begin = i
+		leg++
+	}
+	return improved
+}
+
+// twoOpt: 巡回路の2辺 (a,b), (c,d) を (a,c), (b,d) につなぎ替えて短くなる限り繰り返す
+// path[0] (スタート) は動かさない
+func (aco *ACO) twoOpt(path []int) {
+	n := len(path)
+	if n < 4 {
+		return
+	}
+
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-2; i++ {
+			a, b := path[i], path[i+1]
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					continue // (a,b) と閉じる辺 (c,a) は隣接している
+				}
+				c, d := path[j], path[(j+1)%n]
+				if !aco.hasEdge(a, c) || !aco.hasEdge(b, d) {
+					continue
+				}
+
+				delta := aco.distance(a, c) + aco.distance(b, d) - aco.distance(a, b) - aco.distance(c, d)
+				if delta < -localSearchEpsilon {
+					reverse(path[i+1 : j+1])
+					b = path[i+1]
+					improved = true
+				}
+			}
+		}
+	}
+}
+
+// shortcut: path[i] から path[j] へ直接の辺があり、間を経由するより短ければ間を省く
+func (aco *ACO) shortcut(path []int) []int {
+	for i := 0; i < len(path)-2; i++ {
+		// 遠い方から探すと1回で大きく縮められる
+		for j := len(path) - 1; j > i+1; j-- {
+			if !aco.hasEdge(path[i], path[j]) {
+				continue
+			}
+
+			via := 0.0
+			for k := i; k < j; k++ {
+				via += aco.distance(path[k], path[k+1])
+			}
+			if aco.distance(path[i], path[j]) < via-localSearchEpsilon {
+				path = append(path[:i+1], path[j:]...)
+				break
+			}
+		}
+	}
+	return path
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
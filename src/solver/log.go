@@ -0,0 +1,55 @@
+package solver
+
+import (
+	"fmt"
+	"os"
+)
+
+// レベル付きのログ (探索中の出来事を、呼び出し側が選んだレベル以上だけ出す)
+// 出力先は SetLogOutput で差し替える (WASM では JS の console に送る)。既定は標準エラー出力。
+// レベルと出力先はパッケージ全体で共有する。
+
+// ログのレベル (低い順)
+const (
+	LogDebug = "debug" // ベスト経路の更新など、イテレーションごとに出うるもの
+	LogInfo  = "info"  // インスタンスの生成・読み込み
+	LogWarn  = "warn"
+	LogError = "error" // 呼び出しの失敗
+)
+
+// logLevels: レベル名 → 順位
+var logLevels = map[string]int{LogDebug: 0, LogInfo: 1, LogWarn: 2, LogError: 3}
+
+var (
+	logLevel  = LogInfo
+	logOutput = func(level, message string) {
+		fmt.Fprintf(os.Stderr, "[%s] %s\n", level, message)
+	}
+)
+
+// SetLogLevel: level 以上のログだけを出す
+func SetLogLevel(level string) error {
+	if _, ok := logLevels[level]; !ok {
+		return fmt.Errorf("%w: unknown log level %q (expected %q, %q, %q or %q)", ErrInvalidConfig, level, LogDebug, LogInfo, LogWarn, LogError)
+	}
+	logLevel = level
+	return nil
+}
+
+// LogLevel: 現在のレベル
+func LogLevel() string {
+	return logLevel
+}
+
+// SetLogOutput: ログの出力先を差し替える (level はレベル名、message は改行を含まない)
+func SetLogOutput(output func(level, message string)) {
+	logOutput = output
+}
+
+// Logf: level のログを書式付きで出す (現在のレベル未満なら何もしない)
+func Logf(level, format string, args ...interface{}) {
+	if logLevels[level] < logLevels[logLevel] {
+		return
+	}
+	logOutput(level, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,66 @@
+package solver
+
+import "sort"
+
+// 最小全域木 (ネットワークの骨格。フェロモンが強まる辺と見比べる)
+// 辺の向きは無視し、重み (Weight) の小さい順に Kruskal 法で選ぶ。
+// 連結でなければ成分ごとの最小全域木 (最小全域森) を返す。
+
+// SpanningTree: MinimumSpanningTree の結果
+type SpanningTree struct {
+	// 木に含まれる辺 (Graph.Edges の添字、getPheromones と同じ順で参照できる)
+	Edges   []int   `json:"edges"`
+	Weight  float64 `json:"weight"`  // 重みの合計
+	RawDist float64 `json:"rawDist"` // 座標上の長さの合計
+	// 木の辺に載っているフェロモン量の、全ての辺の合計に対する割合
+	PheromoneShare float64 `json:"pheromoneShare"`
+}
+
+// MinimumSpanningTree: 現在のグラフの最小全域木 (森) を求める
+func (aco *ACO) MinimumSpanningTree() SpanningTree {
+	edges := aco.Graph.Edges
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	// 重みが同じなら添字順 (結果を決定的にする)
+	sort.SliceStable(order, func(a, b int) bool { return edges[order[a]].Weight < edges[order[b]].Weight })
+
+	parent := make([]int, len(aco.Graph.Nodes))
+	for v := range parent {
+		parent[v] = v
+	}
+	var root func(v int) int
+	root = func(v int) int {
+		if parent[v] != v {
+			parent[v] = root(parent[v])
+		}
+		return parent[v]
+	}
+
+	tree := SpanningTree{Edges: []int{}}
+	for _, i := range order {
+		e := edges[i]
+		ru, rv := root(e.From), root(e.To)
+		if ru == rv {
+			continue
+		}
+		parent[ru] = rv
+		tree.Edges = append(tree.Edges, i)
+		tree.Weight += e.Weight
+		tree.RawDist += e.RawDist
+	}
+	sort.Ints(tree.Edges)
+
+	total, inTree := 0.0, 0.0
+	for i, e := range edges {
+		total += aco.pheromone(e.From, e.To)
+		if j := sort.SearchInts(tree.Edges, i); j < len(tree.Edges) && tree.Edges[j] == i {
+			inTree += aco.pheromone(e.From, e.To)
+		}
+	}
+	if total > 0 {
+		tree.PheromoneShare = inTree / total
+	}
+	return tree
+}
@@ -0,0 +1,126 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+)
+
+// 障害物 (Config.Obstacles)
+// 長方形または多角形の壁。グラフ生成では障害物の内側にノードを置かず、障害物を横切る辺を張らない。
+// 生成したグラフの GraphData.Obstacles に多角形として残すので、JS側で壁を描ける。
+// 格子などノードの位置が決まっているトポロジーでは、障害物の内側のノードは辺を持たない孤立点になる。
+// 読み込んだグラフの辺や実行中に追加した辺は検査しない (描画用に形だけ持つ)。
+
+// Obstacle: 障害物 (Points を省略すると X, Y, Width, Height の長方形)
+type Obstacle struct {
+	// 多角形の頂点 [x, y] の列 (3点以上、時計回り・反時計回りどちらでもよい)
+	Points [][2]float64 `json:"points,omitempty"`
+	// 長方形の左上と大きさ
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+}
+
+// randomNodes が障害物の外の位置を探す回数
+const obstacleRetries = 100
+
+// validate: 多角形なら3点以上、長方形なら正の大きさ (エラーの種類は呼び出し側で付ける)
+func (o Obstacle) validate() error {
+	if len(o.Points) > 0 {
+		if len(o.Points) < 3 {
+			return fmt.Errorf("obstacle polygon needs at least 3 points (got %d)", len(o.Points))
+		}
+		return nil
+	}
+	if o.Width <= 0 || o.Height <= 0 {
+		return fmt.Errorf("obstacle needs points or a positive width and height (got %g x %g)", o.Width, o.Height)
+	}
+	return nil
+}
+
+// polygon: 障害物の頂点列 (長方形は4点にする)
+func (o Obstacle) polygon() [][2]float64 {
+	if len(o.Points) > 0 {
+		return o.Points
+	}
+	return [][2]float64{
+		{o.X, o.Y},
+		{o.X + o.Width, o.Y},
+		{o.X + o.Width, o.Y + o.Height},
+		{o.X, o.Y + o.Height},
+	}
+}
+
+// obstaclePolygons: 障害物を全て多角形の形にしたもの (GraphData.Obstacles 用)
+func obstaclePolygons(obstacles []Obstacle) []Obstacle {
+	if len(obstacles) == 0 {
+		return nil
+	}
+	polygons := make([]Obstacle, len(obstacles))
+	for i, o := range obstacles {
+		polygons[i] = Obstacle{Points: o.polygon()}
+	}
+	return polygons
+}
+
+// insideObstacle: 点 (x, y) がいずれかの障害物の内側にあるか
+func insideObstacle(obstacles []Obstacle, x, y float64) bool {
+	for _, o := range obstacles {
+		if pointInPolygon(o.polygon(), x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedByObstacle: 線分 a-b がいずれかの障害物を横切るか (端点が内側にある場合も含む)
+func blockedByObstacle(obstacles []Obstacle, a, b Node) bool {
+	for _, o := range obstacles {
+		polygon := o.polygon()
+		if pointInPolygon(polygon, a.X, a.Y) || pointInPolygon(polygon, b.X, b.Y) {
+			return true
+		}
+		for i := range polygon {
+			p, q := polygon[i], polygon[(i+1)%len(polygon)]
+			if segmentsIntersect(a.X, a.Y, b.X, b.Y, p[0], p[1], q[0], q[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pointInPolygon: 半直線との交差回数の偶奇で内外を判定する
+func pointInPolygon(polygon [][2]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		xi, yi, xj, yj := polygon[i][0], polygon[i][1], polygon[j][0], polygon[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// segmentsIntersect: 線分 a-b と c-d が交わるか (端で接する場合も含む)
+func segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	cross := func(ox, oy, px, py, qx, qy float64) float64 {
+		return (px-ox)*(qy-oy) - (py-oy)*(qx-ox)
+	}
+	onSegment := func(ox, oy, px, py, qx, qy float64) bool {
+		return math.Min(ox, px) <= qx && qx <= math.Max(ox, px) && math.Min(oy, py) <= qy && qy <= math.Max(oy, py)
+	}
+
+	d1 := cross(cx, cy, dx, dy, ax, ay)
+	d2 := cross(cx, cy, dx, dy, bx, by)
+	d3 := cross(ax, ay, bx, by, cx, cy)
+	d4 := cross(ax, ay, bx, by, dx, dy)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(cx, cy, dx, dy, ax, ay)) ||
+		(d2 == 0 && onSegment(cx, cy, dx, dy, bx, by)) ||
+		(d3 == 0 && onSegment(ax, ay, bx, by, cx, cy)) ||
+		(d4 == 0 && onSegment(ax, ay, bx, by, dx, dy))
+}
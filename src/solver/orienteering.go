@@ -0,0 +1,157 @@
+package solver
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+// オリエンテーリング (Config.Mode = "orienteering")
+// ノードに賞金 (Node.Prize) があり、アリは距離の予算 (Config.Budget) の中でスタートからゴールへ向かいながら
+// できるだけ多くの賞金を集める。経路の評価は集めた賞金の合計 (同じなら距離が短い方) になる。
+//   構築: 進んだ後にゴールへ戻れなくなる隣接ノード (ここまでの距離 + 辺 + ゴールまでの最短距離 > 予算) は選ばない
+//   選択: η に行き先の賞金 (+ 最大賞金の OrienteeringPrizeFloor 倍) を掛ける
+//   散布: 距離の代わりに賞金で Q · prize / max(ベストの賞金, prize) を散布する
+
+const (
+	OrienteeringPrizeFloor = 0.1 // 賞金のないノードも通れるよう η に足す、最大賞金に対する割合
+	OrienteeringMaxPrize   = 10  // 生成グラフで各ノードに付ける賞金の上限 (1 以上の整数)
+)
+
+// orienteering: 予算内で賞金を集めるモードか
+func (aco *ACO) orienteering() bool {
+	return aco.Config.Mode == ModeOrienteering
+}
+
+// assignPrizes: 生成グラフの各ノードに 1..OrienteeringMaxPrize の賞金を付ける
+func assignPrizes(nodes []Node, randSource *rand.Rand) {
+	for i := range nodes {
+		nodes[i].Prize = float64(1 + randSource.Intn(OrienteeringMaxPrize))
+	}
+}
+
+// prepareOrienteering: 構築の前に各ノードからゴールまでの最短距離と賞金の下駄を求める
+// (オリエンテーリング以外では何もしない)
+func (aco *ACO) prepareOrienteering() {
+	if !aco.orienteering() {
+		aco.toGoal = nil
+		return
+	}
+	aco.toGoal = aco.distancesToGoals()
+	maxPrize := 0.0
+	for _, node := range aco.Graph.Nodes {
+		maxPrize = math.Max(maxPrize, node.Prize)
+	}
+	aco.prizeFloor = OrienteeringPrizeFloor * maxPrize
+	if aco.prizeFloor == 0 {
+		aco.prizeFloor = 1 // 賞金が1つもなければ距離だけで選ぶ
+	}
+}
+
+// distancesToGoals: 各ノードからいずれかのゴールまでの最短距離 (逆向きの Dijkstra、到達不能は +Inf)
+func (aco *ACO) distancesToGoals() []float64 {
+	n := len(aco.Graph.Nodes)
+	reverse := make([][]Neighbor, n) // reverse[v] は v に入る半辺 (To を出発側に置き換えたもの)
+	for u := range aco.Adj {
+		for _, nb := range aco.Adj[u] {
+			reverse[nb.To] = append(reverse[nb.To], Neighbor{To: u, Dist: nb.Dist})
+		}
+	}
+
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	pq := &priorityQueue{}
+	for _, goal := range aco.goals() {
+		dist[goal] = 0
+		heap.Push(pq, pqItem{node: goal, priority: 0})
+	}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(pqItem)
+		if item.priority > dist[item.node] {
+			continue
+		}
+		for _, nb := range reverse[item.node] {
+			if d := item.priority + nb.Dist; d < dist[nb.To] {
+				dist[nb.To] = d
+				heap.Push(pq, pqItem{node: nb.To, priority: d})
+			}
+		}
+	}
+	return dist
+}
+
+// selectWithinBudget: ここまでの距離 cost から、予算内でゴールへ戻れる隣接ノードだけを候補に selectNext で選ぶ
+// ゴールに入ると経路が終わるので、ゴールは他に進める隣接ノードがないときだけ選ぶ (予算を使い切るまで賞金を集める)。
+// 候補から外すノードは一時的に訪問済みにして、選んだ後で元に戻す。
+// 曲がり角のコストは含めないので、ゴールへ戻れるかは下界による判定になる
+func (aco *ACO) selectWithinBudget(selectNext func(path []int, leg int, visited []bool) int, path []int, leg int, visited []bool, cost float64) int {
+	var unreachable, goals []int
+	for _, nb := range aco.Adj[path[len(path)-1]] {
+		switch {
+		case visited[nb.To]:
+		case cost+nb.Dist+aco.toGoal[nb.To] > aco.Config.Budget:
+			unreachable = append(unreachable, nb.To)
+		case aco.isGoal(nb.To):
+			goals = append(goals, nb.To)
+		default:
+			continue
+		}
+		visited[nb.To] = true
+	}
+	release := func(nodes []int) {
+		for _, v := range nodes {
+			visited[v] = false
+		}
+	}
+
+	next := selectNext(path, leg, visited)
+	release(goals)
+	if next == -1 && len(goals) > 0 {
+		next = selectNext(path, leg, visited)
+	}
+	release(unreachable)
+	return next
+}
+
+// pathPrize: 経路上のノードの賞金の合計 (同じノードは1度だけ数える)
+func (aco *ACO) pathPrize(path []int) float64 {
+	seen := make(map[int]bool, len(path))
+	prize := 0.0
+	for _, v := range path {
+		if !seen[v] {
+			seen[v] = true
+			prize += aco.Graph.Nodes[v].Prize
+		}
+	}
+	return prize
+}
+
+// improvesBest: 賞金 prize・距離 dist の経路が現在のベストより良いか
+// オリエンテーリングでは賞金が多い方 (同じなら短い方)、それ以外は短い方
+func (aco *ACO) improvesBest(prize, dist float64) bool {
+	if aco.orienteering() {
+		return aco.BestPath == nil || prize > aco.BestPrize || (prize == aco.BestPrize && dist < aco.BestDist)
+	}
+	return dist < aco.BestDist
+}
+
+// betterResult: ASrank の順位付けで a が b より上か
+func (aco *ACO) betterResult(a, b AntResult) bool {
+	if aco.orienteering() && a.Prize != b.Prize {
+		return a.Prize > b.Prize
+	}
+	return a.Dist < b.Dist
+}
+
+// depositAmount: 賞金 prize・距離 dist の経路が散布するフェロモン量の基準 (Q/dist、オリエンテーリングでは賞金の割合)
+func (aco *ACO) depositAmount(prize, dist float64) float64 {
+	if !aco.orienteering() {
+		return aco.Config.Q / dist
+	}
+	if best := math.Max(aco.BestPrize, prize); best > 0 {
+		return aco.Config.Q * prize / best
+	}
+	return 0
+}
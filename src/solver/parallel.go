@@ -0,0 +1,93 @@
+package solver
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// constructAll: antCount 匹分の経路を構築する
+// アリ k はイテレーションごとに (Seed, Iteration, k) から決まる専用の乱数ストリームを使うので、
+// Workers の値 (逐次か並列か) によらず同じシードなら同じ経路になる。
+// Workers > 1 の場合はゴルーチンで並列に構築する。
+// ヒューリスティック (とオリエンテーリングのゴールまでの距離) はイテレーションの始めに1度だけ準備する。
+// 構築中はフェロモン・距離を読むだけなので、共有状態へのロックは不要。
+// 結果はアリの番号順に並ぶため、ベスト更新などの後処理は逐次実行時と同じ順序で行われる。
+// 経路・訪問済みの表・選択確率はアリごとの作業領域 (antBuffer) を使い回すので、
+// 長く回し続けてもイテレーションごとの確保はほとんど起きない。
+func (aco *ACO) constructAll(antCount int) []antWalk {
+	aco.walks = resize(aco.walks, antCount)
+	walks := aco.walks
+	for len(aco.antBuffers) < antCount {
+		aco.antBuffers = append(aco.antBuffers, &antBuffer{})
+	}
+	aco.prepareAntParams(aco.antBuffers[:antCount])
+	aco.heuristic = heuristics[aco.Config.Heuristic](aco)
+	aco.prepareOrienteering()
+	aco.stepTargets = aco.stepTargets[:0]
+	for leg := 0; leg <= len(aco.Waypoints); leg++ {
+		aco.stepTargets = append(aco.stepTargets, aco.legTargets(leg))
+	}
+
+	workers := min(max(aco.Config.Workers, 1), antCount)
+	rngs := aco.workerRands(workers)
+	if workers <= 1 {
+		for k := 0; k < antCount; k++ {
+			rngs[0].reseed(aco.Seed, aco.Iteration, k)
+			walks[k] = aco.constructSolution(rngs[0].Rand, aco.antBuffers[k])
+		}
+		return walks
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for k := w; k < antCount; k += workers {
+				rngs[w].reseed(aco.Seed, aco.Iteration, k)
+				walks[k] = aco.constructSolution(rngs[w].Rand, aco.antBuffers[k])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	return walks
+}
+
+// antBuffer: アリ1匹分の作業領域 (イテレーションをまたいで使い回す)
+type antBuffer struct {
+	params               antParams // このイテレーションで使う α・β・q0 (種ごとに異なる)
+	path, legStarts      []int
+	visited, noneVisited []bool
+	costs, probabilities []float64
+}
+
+// resize: s を長さ n のゼロ値の並びにする (容量が足りれば確保しない)
+func resize[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	s = s[:n]
+	clear(s)
+	return s
+}
+
+// antRand: アリごとのストリームに切り替えて使い回す乱数
+type antRand struct {
+	*rand.Rand
+	src randomSource
+}
+
+// reseed: (seed, iteration, ant) で決まるストリームの先頭に切り替える
+func (r antRand) reseed(seed int64, iteration, ant int) {
+	r.src.reseed(uint64(seed), uint64(iteration)<<32|uint64(ant))
+}
+
+// workerRands: ワーカー数分の乱数 (状態は毎回 reseed で決まるので保存不要)
+func (aco *ACO) workerRands(workers int) []antRand {
+	for len(aco.antRands) < workers {
+		randSource, src := newSearchRand(aco.Config, 0)
+		aco.antRands = append(aco.antRands, antRand{Rand: randSource, src: src})
+	}
+	return aco.antRands[:workers]
+}
@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// 実行中のパラメータ変更 (SetParams)
+// アリの数・ステップ上限・α/β・蒸発率などは次の Step から反映できるが、
+// グラフの生成方法や問題の種類、コロニー数はインスタンスを作り直さないと変えられない。
+
+// fixedParams: 実行中に変更できない Config の項目
+var fixedParams = []struct {
+	name string
+	get  func(c Config) interface{}
+}{
+	{"topology", func(c Config) interface{} { return c.Topology }},
+	{"averageDegree", func(c Config) interface{} { return c.AverageDegree }},
+	{"density", func(c Config) interface{} { return c.Density }},
+	{"width", func(c Config) interface{} { return c.Width }},
+	{"height", func(c Config) interface{} { return c.Height }},
+	{"depth", func(c Config) interface{} { return c.Depth }},
+	{"torus", func(c Config) interface{} { return c.Torus }},
+	{"metric", func(c Config) interface{} { return c.Metric }},
+	{"obstacles", func(c Config) interface{} { return c.Obstacles }},
+	{"normalization", func(c Config) interface{} { return c.Normalization }},
+	{"connectComponents", func(c Config) interface{} { return c.ConnectComponents }},
+	{"oneWayRatio", func(c Config) interface{} { return c.OneWayRatio }},
+	{"mode", func(c Config) interface{} { return c.Mode }},
+	{"colonies", func(c Config) interface{} { return c.Colonies }},
+	{"seed", func(c Config) interface{} { return c.Seed }},
+	{"rng", func(c Config) interface{} { return c.RNG }},
+}
+
+// SetParams: 設定を cfg に置き換える (次の Step から有効)
+// 変更できない項目が現在の設定と異なればエラー。コロニーにも同じ設定を適用する
+func (aco *ACO) SetParams(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	for _, param := range fixedParams {
+		if !reflect.DeepEqual(param.get(cfg), param.get(aco.Config)) {
+			return fmt.Errorf("%w: %s cannot be changed at runtime", ErrInvalidConfig, param.name)
+		}
+	}
+
+	// 経路の距離に効くパラメータが変われば測り直す
+	distancesChanged := cfg.Congestion != aco.Config.Congestion || cfg.RiskWeight != aco.Config.RiskWeight ||
+		cfg.HopWeight != aco.Config.HopWeight || cfg.TurnPenalty != aco.Config.TurnPenalty
+	if err := aco.SetRecording(cfg.Record, cfg.RecordPheromones); err != nil {
+		return err
+	}
+	aco.Config = cfg
+	if distancesChanged {
+		aco.rescoreDistances()
+	}
+	for _, colony := range aco.Colonies {
+		colonyConfig := cfg
+		colonyConfig.Colonies, colonyConfig.TopK, colonyConfig.Pareto, colonyConfig.Record = 0, 0, 0, 0
+		if err := colony.SetParams(colonyConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
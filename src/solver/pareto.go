@@ -0,0 +1,95 @@
+package solver
+
+import "sort"
+
+// 多目的 (距離・危険度・辺数)
+// アリは RiskWeight・HopWeight による重み付き和 (Neighbor.Dist) で経路を選ぶが、
+// 見つかった経路は目的ごとの値でも比べ、どれにも劣らない経路をパレート解として保持する (Config.Pareto)。
+// 重みを変えながら回せば、前線の別の部分を探させることができる。
+
+// ParetoPath: パレート解の1本
+type ParetoPath struct {
+	// 重み付き和の項を除いた距離 (辺の重み + 通過コスト、混雑モードなら混雑込み)
+	Dist float64 `json:"dist"`
+	Risk float64 `json:"risk"`
+	Hops int     `json:"hops"`
+	Path []int   `json:"path"`
+}
+
+// dominates: a が b に優越するか (全ての目的で b 以下、少なくとも1つで b より小さい)
+func (a ParetoPath) dominates(b ParetoPath) bool {
+	if a.Dist > b.Dist || a.Risk > b.Risk || a.Hops > b.Hops {
+		return false
+	}
+	return a.Dist < b.Dist || a.Risk < b.Risk || a.Hops < b.Hops
+}
+
+// paretoObjectives: 重み付き和の距離 dist で測った経路の目的ごとの値
+func (aco *ACO) paretoObjectives(path []int, dist float64) ParetoPath {
+	hops := len(path) - 1
+	if aco.Config.Mode == ModeTSP && len(path) > 1 {
+		hops++
+	}
+	risk := 0.0
+	for i := 0; i < hops; i++ {
+		if nb := aco.neighbor(path[i], path[(i+1)%len(path)]); nb != nil {
+			risk += nb.Risk
+		}
+	}
+	return ParetoPath{
+		Dist: dist - aco.Config.RiskWeight*risk - aco.Config.HopWeight*float64(hops),
+		Risk: risk,
+		Hops: hops,
+		Path: path,
+	}
+}
+
+// recordPareto: path がどのパレート解にも優越されなければ追加し、優越される解を外す
+// 保持数 Config.Pareto を超えたら距離の最も長い解から外す
+func (aco *ACO) recordPareto(path []int, dist float64) {
+	if aco.Config.Pareto <= 0 {
+		return
+	}
+	candidate := aco.paretoObjectives(path, dist)
+	for _, p := range aco.ParetoFront {
+		if p.dominates(candidate) || (p.Dist == candidate.Dist && p.Risk == candidate.Risk && p.Hops == candidate.Hops) {
+			return
+		}
+	}
+
+	kept := aco.ParetoFront[:0]
+	for _, p := range aco.ParetoFront {
+		if !candidate.dominates(p) {
+			kept = append(kept, p)
+		}
+	}
+	candidate.Path = append([]int(nil), path...)
+	i := sort.Search(len(kept), func(i int) bool { return kept[i].Dist > candidate.Dist })
+	kept = append(kept, ParetoPath{})
+	copy(kept[i+1:], kept[i:])
+	kept[i] = candidate
+	if len(kept) > aco.Config.Pareto {
+		kept = kept[:aco.Config.Pareto]
+	}
+	aco.ParetoFront = kept
+}
+
+// rescorePareto: 重みの変更後に目的値を計算し直し、優越された解を外す
+func (aco *ACO) rescorePareto() {
+	front := aco.ParetoFront
+	aco.ParetoFront = nil
+	for _, p := range front {
+		aco.recordPareto(p.Path, aco.calculatePathDistance(p.Path))
+	}
+}
+
+// filterPareto: keep が false を返す経路をパレート解から外す
+func (aco *ACO) filterPareto(keep func(path []int) bool) {
+	kept := aco.ParetoFront[:0]
+	for _, p := range aco.ParetoFront {
+		if keep(p.Path) {
+			kept = append(kept, p)
+		}
+	}
+	aco.ParetoFront = kept
+}
@@ -0,0 +1,56 @@
+package solver
+
+// フェロモンをたどる経路 (コロニーが今「正しい」と信じている経路)
+// どのアリもまだ通っていない経路でも、フェロモンの分布が示す道筋を決定的に取り出せる。
+// 現在のノードから、まだ通っていないノードへの半辺のうちフェロモンの最も濃いものを選び続ける
+// (同じ濃さなら距離の短い方)。同じノードを2度通らないので必ず止まり、
+// 未訪問の隣がなくなればそこで行き詰まりとして打ち切る。
+
+// PheromonePath: ExtractPheromonePath の結果
+type PheromonePath struct {
+	Path    []int   `json:"path"` // たどった経路 (行き詰まったらそこまで)
+	Dist    float64 `json:"dist"` // 通った半辺の Dist の合計
+	Reached bool    `json:"reached"`
+	// 経路上の半辺のフェロモンの最小値 (経路の最も弱い箇所、1歩も進まなければ 0)
+	MinPheromone float64 `json:"minPheromone"`
+}
+
+// ExtractPheromonePath: start からフェロモンの最も濃い辺をたどって goal を目指す
+func (aco *ACO) ExtractPheromonePath(start, goal int) (PheromonePath, error) {
+	if err := aco.checkNode(start); err != nil {
+		return PheromonePath{}, err
+	}
+	if err := aco.checkNode(goal); err != nil {
+		return PheromonePath{}, err
+	}
+	return aco.greedyPath(start, goal), nil
+}
+
+// greedyPath: ExtractPheromonePath の本体 (ノードは検証済み)
+func (aco *ACO) greedyPath(start, goal int) PheromonePath {
+	visited := make([]bool, len(aco.Graph.Nodes))
+	result := PheromonePath{Path: []int{start}}
+	current := start
+	visited[current] = true
+	for current != goal {
+		var next *Neighbor
+		for k := range aco.Adj[current] {
+			// 隣接リストは距離の昇順なので、同じ濃さなら先に見た (短い) 方が残る
+			if nb := &aco.Adj[current][k]; !visited[nb.To] && (next == nil || nb.Pheromone > next.Pheromone) {
+				next = nb
+			}
+		}
+		if next == nil {
+			return result
+		}
+		if len(result.Path) == 1 || next.Pheromone < result.MinPheromone {
+			result.MinPheromone = next.Pheromone
+		}
+		result.Dist += next.Dist
+		result.Path = append(result.Path, next.To)
+		current = next.To
+		visited[current] = true
+	}
+	result.Reached = true
+	return result
+}
@@ -0,0 +1,73 @@
+package solver
+
+import "time"
+
+// Progress: 長い実行の途中経過
+type Progress struct {
+	Iteration int     `json:"iteration"`
+	BestDist  float64 `json:"bestDist"`
+	BestPath  []int   `json:"bestPath"`
+}
+
+// ProgressHook: Every イテレーションごとに Func へ途中経過を通知する (Every <= 0 または Func が nil なら通知しない)
+type ProgressHook struct {
+	Every int
+	Func  func(Progress)
+}
+
+// notify: 現在のイテレーションが Every の倍数なら Func を呼ぶ
+func (h ProgressHook) notify(aco *ACO) {
+	if h.Func == nil || h.Every <= 0 || aco.Iteration%h.Every != 0 {
+		return
+	}
+	h.Func(Progress{Iteration: aco.Iteration, BestDist: aco.BestDist, BestPath: aco.BestPath})
+}
+
+// RunWithProgress: Run と同じく Step を繰り返し、hook に途中経過を通知する
+func (aco *ACO) RunWithProgress(iterations int, hook ProgressHook) ([]float64, error) {
+	history := make([]float64, 0, iterations)
+	for i := 0; i < iterations; i++ {
+		if aco.Step(); aco.stepErr != nil {
+			return history, aco.stepErr
+		}
+		history = append(history, aco.BestDist)
+		hook.notify(aco)
+	}
+	return history, nil
+}
+
+// TimedRun: RunFor の結果
+type TimedRun struct {
+	// 実行したイテレーション番号の範囲 (From..To、両端を含む)
+	From       int       `json:"from"`
+	To         int       `json:"to"`
+	Iterations int       `json:"iterations"`
+	ElapsedMs  float64   `json:"elapsedMs"`
+	History    []float64 `json:"history"` // 各イテレーション後のベスト距離
+}
+
+// RunFor: 経過時間が budget に達するまで Step を繰り返す (少なくとも1回、maxIterations > 0 ならその回数まで)
+// 直前の Step と同じだけかかると予算を超える場合はそこで止めるので、描画フレームごとの持ち時間に収めやすい
+func (aco *ACO) RunFor(budget time.Duration, maxIterations int, hook ProgressHook) (TimedRun, error) {
+	start := time.Now()
+	run := TimedRun{From: aco.Iteration + 1}
+	var last time.Duration
+	for {
+		stepStart := time.Now()
+		if aco.Step(); aco.stepErr != nil {
+			return TimedRun{}, aco.stepErr
+		}
+		last = time.Since(stepStart)
+		run.History = append(run.History, aco.BestDist)
+		hook.notify(aco)
+
+		elapsed := time.Since(start)
+		if elapsed+last > budget || (maxIterations > 0 && len(run.History) >= maxIterations) {
+			break
+		}
+	}
+	run.To = aco.Iteration
+	run.Iterations = len(run.History)
+	run.ElapsedMs = float64(time.Since(start)) / float64(time.Millisecond)
+	return run, nil
+}
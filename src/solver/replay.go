@@ -0,0 +1,112 @@
+package solver
+
+import (
+	"fmt"
+	"slices"
+)
+
+// 記録モード (Config.Record)
+// イテレーションごとのベスト経路 (と任意でフェロモンの要約) を直近 Record 回分だけリングバッファに残し、
+// フロントエンドが過去のイテレーションへ巻き戻して表示できるようにする。スナップショットには含めない。
+
+// ReplayFrame: 1イテレーション分の記録
+type ReplayFrame struct {
+	Iteration int     `json:"iteration"`
+	BestDist  float64 `json:"bestDist"`
+	BestPath  []int   `json:"bestPath"`
+	// フェロモン量の要約 (Config.RecordPheromones のときのみ)
+	Pheromone *PheromoneSummary `json:"pheromone,omitempty"`
+}
+
+// Replay: GetReplay の結果
+type Replay struct {
+	// 記録が残っている最古・最新のイテレーション (記録がなければ 0)
+	First  int           `json:"first"`
+	Last   int           `json:"last"`
+	Frames []ReplayFrame `json:"frames"`
+}
+
+// replayBuffer: 容量 Config.Record のリングバッファ (start が最古のフレーム)
+type replayBuffer struct {
+	frames []ReplayFrame
+	start  int
+}
+
+// recordFrame: 記録モードなら現在の状態をリングバッファに追加する (満杯なら最古を上書き)
+func (aco *ACO) recordFrame() {
+	limit := aco.Config.Record
+	if limit <= 0 {
+		return
+	}
+	frame := ReplayFrame{
+		Iteration: aco.Iteration,
+		BestDist:  aco.BestDist,
+		BestPath:  append([]int(nil), aco.BestPath...),
+	}
+	if aco.Config.RecordPheromones {
+		summary := aco.PheromoneSummary()
+		frame.Pheromone = &summary
+	}
+
+	r := &aco.replay
+	if len(r.frames) < limit {
+		r.frames = append(r.frames, frame)
+		return
+	}
+	r.frames[r.start] = frame
+	r.start = (r.start + 1) % len(r.frames)
+}
+
+// ordered: 記録を古い順に並べたもの
+func (r *replayBuffer) ordered() []ReplayFrame {
+	return append(slices.Clone(r.frames[r.start:]), r.frames[:r.start]...)
+}
+
+// SetRecording: 記録モードの容量とフェロモン要約の有無を変える
+// 容量を減らした場合は新しいものから limit 個を残す (0で記録をやめて破棄する)
+func (aco *ACO) SetRecording(limit int, pheromones bool) error {
+	if limit < 0 {
+		return fmt.Errorf("%w: record must be >= 0 (got %d)", ErrInvalidConfig, limit)
+	}
+	frames := aco.replay.ordered()
+	if len(frames) > limit {
+		frames = frames[len(frames)-limit:]
+	}
+	aco.replay = replayBuffer{frames: frames}
+	aco.Config.Record, aco.Config.RecordPheromones = limit, pheromones
+	return nil
+}
+
+// GetReplay: from 以上 to 以下のイテレーションの記録を古い順に返す
+// 範囲外は残っている分だけに切り詰める
+func (aco *ACO) GetReplay(from, to int) (Replay, error) {
+	if from > to {
+		return Replay{}, fmt.Errorf("%w: from must be <= to (got %d, %d)", ErrInvalidConfig, from, to)
+	}
+	replay := Replay{Frames: []ReplayFrame{}}
+	frames := aco.replay.ordered()
+	if len(frames) == 0 {
+		return replay, nil
+	}
+	replay.First, replay.Last = frames[0].Iteration, frames[len(frames)-1].Iteration
+	for _, frame := range frames {
+		if frame.Iteration >= from && frame.Iteration <= to {
+			replay.Frames = append(replay.Frames, frame)
+		}
+	}
+	return replay, nil
+}
+
+// remapReplay: ノード削除に合わせて記録の経路を付け替える (削除ノードを通る経路は消す)
+func (aco *ACO) remapReplay(id int, remap func(int) int) {
+	for i := range aco.replay.frames {
+		frame := &aco.replay.frames[i]
+		if slices.Contains(frame.BestPath, id) {
+			frame.BestPath = nil
+			continue
+		}
+		for k, v := range frame.BestPath {
+			frame.BestPath[k] = remap(v)
+		}
+	}
+}
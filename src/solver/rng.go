@@ -0,0 +1,166 @@
+package solver
+
+import (
+	crand "crypto/rand"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"sort"
+)
+
+// 乱数源の切り替え (Config.RNG)
+// グラフの生成はシードから PCG で行い、探索 (アリの経路構築・コロニーの分岐など) には Config.RNG の乱数源を使う。
+// どの乱数源でもアリ k はイテレーションごとに reseed で (Seed, Iteration, k) のストリームに切り替える。
+// "crypto" はシードを使わないので再現性はなく、スナップショットにも状態を持たない。
+
+// 乱数源の種類
+const (
+	RNGPCG     = "pcg"     // math/rand/v2 の PCG (既定)
+	RNGCounter = "counter" // カウンタのハッシュで作る乱数 (n 番目の値が (シード, ストリーム, n) だけで決まる)
+	RNGCrypto  = "crypto"  // 暗号論的乱数 (Config.Entropy、なければ crypto/rand。WASM では crypto.getRandomValues)
+)
+
+// randomSource: 探索に使う乱数源 (Rand の中身として ACO に持つ)
+type randomSource interface {
+	rand.Source64
+	// reseed: (seed, stream) で決まる系列の先頭に切り替える
+	reseed(seed, stream uint64)
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// randomSources: 乱数源の種類 → 作成 (シードは呼び出し側が与える)
+var randomSources = map[string]func(cfg Config) randomSource{
+	RNGPCG:     func(Config) randomSource { return pcgSource{&randv2.PCG{}} },
+	RNGCounter: func(Config) randomSource { return &counterSource{} },
+	RNGCrypto:  func(cfg Config) randomSource { return newCryptoSource(cfg.Entropy) },
+}
+
+// RNGNames: 選べる乱数源 (ソート済み)
+func RNGNames() []string {
+	names := make([]string, 0, len(randomSources))
+	for name := range randomSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pcgSource: 状態を保存・復元できる乱数源 (PCG)
+// math/rand の Source は内部状態を取り出せないので、saveState のために
+// math/rand/v2 の PCG を math/rand の Source64 として包んで使う。
+type pcgSource struct {
+	*randv2.PCG
+}
+
+func (s pcgSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s pcgSource) Seed(seed int64) {
+	s.PCG.Seed(uint64(seed), 0)
+}
+
+func (s pcgSource) reseed(seed, stream uint64) {
+	s.PCG.Seed(seed, stream)
+}
+
+// counterSource: n 番目の値を (key, n) のハッシュ (SplitMix64) で作る乱数源
+// 状態はカウンタだけなので、どのワーカーが担当しても同じストリームを同じ順で引ける。
+type counterSource struct {
+	key, counter uint64
+}
+
+func (s *counterSource) Uint64() uint64 {
+	s.counter++
+	return mix64(s.key + s.counter*0x9e3779b97f4a7c15)
+}
+
+func (s *counterSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *counterSource) Seed(seed int64) {
+	s.reseed(uint64(seed), 0)
+}
+
+func (s *counterSource) reseed(seed, stream uint64) {
+	s.key, s.counter = mix64(seed^mix64(stream)), 0
+}
+
+func (s *counterSource) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(nil, s.key), s.counter), nil
+}
+
+func (s *counterSource) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 {
+		return fmt.Errorf("counter rng state must be 16 bytes (got %d)", len(data))
+	}
+	s.key, s.counter = binary.BigEndian.Uint64(data), binary.BigEndian.Uint64(data[8:])
+	return nil
+}
+
+// mix64: SplitMix64 の出力関数
+func mix64(x uint64) uint64 {
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// cryptoSource: 外から与えたバイト列を読む乱数源 (シードは無視し、状態も持たない)
+// 呼び出しのたびに読むと遅いので、まとめて読んで少しずつ使う
+type cryptoSource struct {
+	read func(p []byte)
+	buf  []byte
+}
+
+func newCryptoSource(read func(p []byte)) *cryptoSource {
+	if read == nil {
+		read = func(p []byte) { crand.Read(p) }
+	}
+	return &cryptoSource{read: read}
+}
+
+func (s *cryptoSource) Uint64() uint64 {
+	if len(s.buf) < 8 {
+		s.buf = make([]byte, 1024)
+		s.read(s.buf)
+	}
+	v := binary.LittleEndian.Uint64(s.buf)
+	s.buf = s.buf[8:]
+	return v
+}
+
+func (s *cryptoSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *cryptoSource) Seed(int64)            {}
+func (s *cryptoSource) reseed(uint64, uint64) {}
+
+func (s *cryptoSource) MarshalBinary() ([]byte, error) { return nil, nil }
+func (s *cryptoSource) UnmarshalBinary([]byte) error   { return nil }
+
+// newRand: seed から再現可能な乱数と、その状態を持つ乱数源を作る (PCG)
+func newRand(seed int64) (*rand.Rand, pcgSource) {
+	src := pcgSource{randv2.NewPCG(uint64(seed), 0)}
+	return rand.New(src), src
+}
+
+// newSearchRand: 探索用に Config.RNG の乱数源を seed で作る
+func newSearchRand(cfg Config, seed int64) (*rand.Rand, randomSource) {
+	src := randomSources[cfg.RNG](cfg)
+	src.Seed(seed)
+	return rand.New(src), src
+}
+
+// restoreRand: MarshalBinary で保存した状態から Config.RNG の乱数を復元する
+func restoreRand(cfg Config, state []byte) (*rand.Rand, randomSource, error) {
+	src := randomSources[cfg.RNG](cfg)
+	if err := src.UnmarshalBinary(state); err != nil {
+		return nil, nil, err
+	}
+	return rand.New(src), src, nil
+}
@@ -0,0 +1,213 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// 焼きなまし法 (比較用のもう一つのメタヒューリスティック、経路探索モードのみ)
+// 解はスタート→経由地→ゴールの区間ごとの単純路。近傍は区間内の部分経路をランダムな迂回路で置き換えたもの。
+// 経由地は区間の端に固定し、最後の区間の終点だけはどのゴールに付け替えてもよい。
+
+// 焼きなまし法の既定のパラメータ
+const (
+	SAIterations  = 5000
+	SAInitialTemp = 0.1
+	SACooling     = 0.999
+)
+
+// SAConfig: SolveSA のパラメータ
+type SAConfig struct {
+	Iterations int `json:"iterations"`
+	// 初期温度 (初期解の距離に対する比。0 なら改悪を受理しない山登り法)
+	InitialTemp float64 `json:"initialTemp"`
+	// 1イテレーションごとに温度に掛ける冷却率
+	Cooling float64 `json:"cooling"`
+	// 乱数シード (省略時はインスタンスの Seed)
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+func DefaultSAConfig() SAConfig {
+	return SAConfig{
+		Iterations:  SAIterations,
+		InitialTemp: SAInitialTemp,
+		Cooling:     SACooling,
+	}
+}
+
+// Validate: 焼きなましが破綻する値を弾く
+func (c SAConfig) Validate() error {
+	if c.Iterations < 1 {
+		return fmt.Errorf("%w: iterations must be >= 1 (got %d)", ErrInvalidConfig, c.Iterations)
+	}
+	if c.InitialTemp < 0 {
+		return fmt.Errorf("%w: initialTemp must be >= 0 (got %g)", ErrInvalidConfig, c.InitialTemp)
+	}
+	if c.Cooling <= 0 || c.Cooling > 1 {
+		return fmt.Errorf("%w: cooling must be in (0, 1] (got %g)", ErrInvalidConfig, c.Cooling)
+	}
+	return nil
+}
+
+// SAResult: SolveSA の結果
+type SAResult struct {
+	Dist       float64   `json:"dist"`
+	Path       []int     `json:"path"`
+	Iterations int       `json:"iterations"`
+	Accepted   int       `json:"accepted"`  // 受理した近傍解の数 (改悪を含む)
+	FinalTemp  float64   `json:"finalTemp"` // 終了時の温度
+	History    []float64 `json:"history"`   // 各イテレーション後のベスト距離
+}
+
+// SolveSA: 現在のグラフ・スタート・経由地・ゴールで焼きなまし法を実行する
+// ACO のフェロモンや乱数には触れないので、同じ問題で ACO と並べて比較できる
+func (aco *ACO) SolveSA(cfg SAConfig) (SAResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return SAResult{}, err
+	}
+	if aco.Config.Mode != ModeRoute {
+		return SAResult{}, fmt.Errorf("%w: simulated annealing is only supported in %q mode", ErrInvalidConfig, ModeRoute)
+	}
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return SAResult{}, err
+	}
+	seed := aco.Seed
+	if cfg.Seed != nil {
+		seed = *cfg.Seed
+	}
+	rng, _ := newRand(seed)
+
+	// 初期解: 区間ごとのランダムな単純路
+	legs := make([][]int, len(aco.Waypoints)+1)
+	source := aco.StartNode
+	for l := range legs {
+		targets := aco.legTargets(l)
+		legs[l] = aco.randomRoute(source, targets, make([]bool, len(aco.Adj)), rng)
+		if legs[l] == nil {
+			return SAResult{}, fmt.Errorf("%w: no path from %d to %v", ErrUnreachable, source, targets)
+		}
+		source = legs[l][len(legs[l])-1]
+	}
+
+	current := aco.calculatePathDistance(joinLegs(legs))
+	best, bestPath := current, joinLegs(legs)
+	temp := cfg.InitialTemp * current
+	result := SAResult{Iterations: cfg.Iterations, History: make([]float64, 0, cfg.Iterations)}
+
+	for it := 0; it < cfg.Iterations; it++ {
+		if l, candidate := aco.rerouteSegment(legs, rng); candidate != nil {
+			saved := legs[l]
+			legs[l] = candidate
+			dist := aco.calculatePathDistance(joinLegs(legs))
+			if dist <= current || (temp > 0 && rng.Float64() < math.Exp((current-dist)/temp)) {
+				current = dist
+				result.Accepted++
+				if dist < best {
+					best, bestPath = dist, joinLegs(legs)
+				}
+			} else {
+				legs[l] = saved
+			}
+		}
+		temp *= cfg.Cooling
+		result.History = append(result.History, best)
+	}
+
+	result.Dist, result.Path, result.FinalTemp = best, bestPath, temp
+	return result, nil
+}
+
+// rerouteSegment: ランダムな区間の部分経路 leg[i..j] を別の迂回路に置き換えた区間を返す (見つからなければ nil)
+func (aco *ACO) rerouteSegment(legs [][]int, rng *rand.Rand) (int, []int) {
+	l := rng.Intn(len(legs))
+	leg := legs[l]
+	if len(leg) < 2 {
+		return l, nil
+	}
+	i := rng.Intn(len(leg) - 1)
+	j := i + 1 + rng.Intn(len(leg)-1-i)
+
+	// 置き換えない部分のノードは通れない (区間を単純路に保つ)
+	blocked := make([]bool, len(aco.Adj))
+	for _, v := range leg[:i] {
+		blocked[v] = true
+	}
+	for _, v := range leg[j+1:] {
+		blocked[v] = true
+	}
+
+	targets := []int{leg[j]}
+	if last := l == len(legs)-1; last && j == len(leg)-1 {
+		targets = aco.legTargets(l) // 終点はどのゴールでもよい
+	} else if last {
+		// 途中でゴールに着くと ACO ではそこで終わる経路になるので通らない
+		for _, g := range aco.goals() {
+			if g != leg[j] {
+				blocked[g] = true
+			}
+		}
+	}
+
+	detour := aco.randomRoute(leg[i], targets, blocked, rng)
+	if detour == nil {
+		return l, nil
+	}
+	candidate := make([]int, 0, i+len(detour)+len(leg)-j-1)
+	candidate = append(candidate, leg[:i]...)
+	candidate = append(candidate, detour...)
+	return l, append(candidate, leg[j+1:]...)
+}
+
+// randomRoute: source から targets のいずれかへの単純路を、隣接ノードをランダムな順に試す深さ優先探索で求める
+// blocked のノードは通らない (探索中に書き換える)。到達できなければ nil
+func (aco *ACO) randomRoute(source int, targets []int, blocked []bool, rng *rand.Rand) []int {
+	isTarget := targetSet(len(aco.Adj), targets)
+	if isTarget[source] {
+		return []int{source}
+	}
+
+	visited := blocked
+	visited[source] = true
+	path := []int{source}
+	options := [][]int{aco.shuffledNeighbors(source, rng)} // 各深さで未試行の隣接ノード
+	for len(path) > 0 {
+		top := len(path) - 1
+		if len(options[top]) == 0 {
+			// 行き止まり (visited のまま残して再訪しない)
+			path, options = path[:top], options[:top]
+			continue
+		}
+		v := options[top][len(options[top])-1]
+		options[top] = options[top][:len(options[top])-1]
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+		path = append(path, v)
+		if isTarget[v] {
+			return path
+		}
+		options = append(options, aco.shuffledNeighbors(v, rng))
+	}
+	return nil
+}
+
+// shuffledNeighbors: u から進める隣接ノードをランダムな順に並べたもの
+func (aco *ACO) shuffledNeighbors(u int, rng *rand.Rand) []int {
+	neighbors := make([]int, len(aco.Adj[u]))
+	for k, nb := range aco.Adj[u] {
+		neighbors[k] = nb.To
+	}
+	rng.Shuffle(len(neighbors), func(a, b int) { neighbors[a], neighbors[b] = neighbors[b], neighbors[a] })
+	return neighbors
+}
+
+// joinLegs: 区間をつないだ経路 (区間の境目のノードは1回だけ)
+func joinLegs(legs [][]int) []int {
+	path := append([]int(nil), legs[0]...)
+	for _, leg := range legs[1:] {
+		path = append(path, leg[1:]...)
+	}
+	return path
+}
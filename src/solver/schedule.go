@@ -0,0 +1,45 @@
+package solver
+
+import "math"
+
+// 蒸発率スケジュール (Config.EvaporationSchedule)
+// Evaporation から EvaporationEnd へ EvaporationHorizon イテレーションかけて変化し、以降は EvaporationEnd のまま
+const (
+	ScheduleConstant    = "constant"    // 常に Evaporation
+	ScheduleLinear      = "linear"      // 線形に変化
+	ScheduleExponential = "exponential" // 比が一定になるよう指数的に変化 (両端とも > 0)
+	ScheduleCosine      = "cosine"      // 序盤と終盤はゆっくり、中盤で大きく変化
+)
+
+// 蒸発率スケジュールの既定の終端値と期間
+const (
+	EvaporationEnd     = 0.1
+	EvaporationHorizon = 500
+)
+
+// evaporationRate: 現在のイテレーションで使う蒸発率
+// 自動調整は残る割合 (1 - 蒸発率) を倍率で割るので、上げても 1 には届かない
+func (aco *ACO) evaporationRate() float64 {
+	return math.Max(0, 1-(1-aco.scheduledEvaporation())/aco.adaptiveScale(aco.Adaptive.Evaporation))
+}
+
+// scheduledEvaporation: スケジュールに従った現在の蒸発率
+func (aco *ACO) scheduledEvaporation() float64 {
+	cfg := aco.Config
+	if cfg.EvaporationSchedule == ScheduleConstant || cfg.EvaporationHorizon <= 0 {
+		return cfg.Evaporation
+	}
+
+	// 1回目の Step で t=0、EvaporationHorizon+1 回目以降で t=1
+	t := math.Min(1, math.Max(0, float64(aco.Iteration-1)/float64(cfg.EvaporationHorizon)))
+	start, end := cfg.Evaporation, cfg.EvaporationEnd
+	switch cfg.EvaporationSchedule {
+	case ScheduleLinear:
+		return start + (end-start)*t
+	case ScheduleExponential:
+		return start * math.Pow(end/start, t)
+	case ScheduleCosine:
+		return end + (start-end)*(1+math.Cos(math.Pi*t))/2
+	}
+	return start
+}
@@ -0,0 +1,204 @@
+package solver
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// PathResult: 厳密解法の結果
+type PathResult struct {
+	Dist     float64 `json:"dist"`
+	Path     []int   `json:"path"`
+	Expanded int     `json:"expanded"` // 確定(展開)したノード数
+}
+
+// SolveDijkstra: 現在のグラフでスタートからゴールへの真の最短経路を求める
+// 経由地があれば区間ごとの最短経路をつなぐ (複数ゴールなら最も近いゴールへ)
+func (aco *ACO) SolveDijkstra() (PathResult, error) {
+	if err := aco.CheckPositiveWeights(); err != nil {
+		return PathResult{}, err
+	}
+	return aco.solveLegs(aco.dijkstra)
+}
+
+// CheckPositiveWeights: 全ての半辺の距離が正かを調べる
+// ACO (1/dist のヒューリスティック)・Dijkstra・A* は正の距離が前提で、負の重みを扱えるのは SolveBellmanFord だけ。
+// Step・中心性・経路の取り出し・その他の解法はこれで調べ、負の重みがあればエラーにする
+func (aco *ACO) CheckPositiveWeights() error {
+	for u, neighbors := range aco.Adj {
+		for _, nb := range neighbors {
+			if nb.Dist <= 0 {
+				return fmt.Errorf("%w: edge %d->%d has non-positive distance %g (only bellman-ford supports negative weights)", ErrInvalidGraph, u, nb.To, nb.Dist)
+			}
+		}
+	}
+	return nil
+}
+
+// SolveBellmanFord: 負の重みがあっても解ける最短経路 (Bellman-Ford 法)
+// スタートから到達できる負の閉路があれば ErrNegativeCycle を返す
+func (aco *ACO) SolveBellmanFord() (PathResult, error) {
+	return aco.solveLegs(aco.bellmanFord)
+}
+
+// solveLegs: スタート→経由地→ゴールの各区間を solve で解いてつなぐ
+func (aco *ACO) solveLegs(solve func(source int, targets []int) (PathResult, error)) (PathResult, error) {
+	source := aco.StartNode
+	result := PathResult{Path: []int{source}}
+	for i := 0; i <= len(aco.Waypoints); i++ {
+		leg, err := solve(source, aco.legTargets(i))
+		if err != nil {
+			return PathResult{}, err
+		}
+		result.Dist += leg.Dist
+		result.Path = append(result.Path, leg.Path[1:]...)
+		result.Expanded += leg.Expanded
+		source = leg.Path[len(leg.Path)-1]
+	}
+	return result, nil
+}
+
+// targetSet: ノードIDごとに targets に含まれるかを引ける表
+func targetSet(n int, targets []int) []bool {
+	isTarget := make([]bool, n)
+	for _, t := range targets {
+		isTarget[t] = true
+	}
+	return isTarget
+}
+
+// dijkstra: source から targets のうち最も近いノードへの最短経路
+func (aco *ACO) dijkstra(source int, targets []int) (PathResult, error) {
+	n := len(aco.Graph.Nodes)
+	isTarget := targetSet(n, targets)
+	dist := make([]float64, n)
+	prev := make([]int, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[source] = 0
+
+	expanded, reached := 0, -1
+	pq := &priorityQueue{{node: source, priority: 0}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(pqItem)
+		u := item.node
+		if item.priority > dist[u] {
+			continue // 古いエントリ
+		}
+		expanded++
+		if isTarget[u] {
+			reached = u
+			break
+		}
+		for _, nb := range aco.Adj[u] {
+			v, w := nb.To, nb.Dist
+			if alt := dist[u] + w; alt < dist[v] {
+				dist[v] = alt
+				prev[v] = u
+				heap.Push(pq, pqItem{node: v, priority: alt})
+			}
+		}
+	}
+
+	return buildPathResult(source, targets, reached, dist, prev, expanded)
+}
+
+// bellmanFord: 全ての辺の緩和を最大 n-1 回繰り返し、source から targets のうち最も近いノードへの最短経路を求める
+// n 回目でも緩和できれば負の閉路がある
+func (aco *ACO) bellmanFord(source int, targets []int) (PathResult, error) {
+	n := len(aco.Graph.Nodes)
+	dist := make([]float64, n)
+	prev := make([]int, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[source] = 0
+
+	expanded := 0
+	for pass := 0; pass < n; pass++ {
+		relaxed := -1
+		for u := range aco.Adj {
+			if math.IsInf(dist[u], 1) {
+				continue
+			}
+			expanded++
+			for _, nb := range aco.Adj[u] {
+				if alt := dist[u] + nb.Dist; alt < dist[nb.To] {
+					dist[nb.To] = alt
+					prev[nb.To] = u
+					relaxed = nb.To
+				}
+			}
+		}
+		if relaxed == -1 {
+			break
+		}
+		if pass == n-1 {
+			return PathResult{}, fmt.Errorf("%w: %v is reachable from %d", ErrNegativeCycle, negativeCycle(relaxed, prev), source)
+		}
+	}
+
+	reached := -1
+	for _, t := range targets {
+		if !math.IsInf(dist[t], 1) && (reached == -1 || dist[t] < dist[reached]) {
+			reached = t
+		}
+	}
+	return buildPathResult(source, targets, reached, dist, prev, expanded)
+}
+
+// negativeCycle: n 回目に緩和されたノード v から prev をたどって負の閉路を取り出す
+func negativeCycle(v int, prev []int) []int {
+	// n 回さかのぼれば必ず閉路の上にいる
+	for range prev {
+		v = prev[v]
+	}
+	cycle := []int{v}
+	for u := prev[v]; u != v; u = prev[u] {
+		cycle = append(cycle, u)
+	}
+	cycle = append(cycle, v)
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return cycle
+}
+
+// buildPathResult: prev 配列から到達した target までの経路を復元する (reached が -1 なら到達不能)
+func buildPathResult(source int, targets []int, reached int, dist []float64, prev []int, expanded int) (PathResult, error) {
+	if reached == -1 {
+		return PathResult{}, fmt.Errorf("%w: no path from %d to %v", ErrUnreachable, source, targets)
+	}
+
+	path := []int{}
+	for v := reached; v != -1; v = prev[v] {
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return PathResult{Dist: dist[reached], Path: path, Expanded: expanded}, nil
+}
+
+// priorityQueue: priority が小さい順に取り出す最小ヒープ
+type pqItem struct {
+	node     int
+	priority float64
+}
+
+type priorityQueue []pqItem
+
+func (pq priorityQueue) Len() int            { return len(pq) }
+func (pq priorityQueue) Less(i, j int) bool  { return pq[i].priority < pq[j].priority }
+func (pq priorityQueue) Swap(i, j int)       { pq[i], pq[j] = pq[j], pq[i] }
+func (pq *priorityQueue) Push(x interface{}) { *pq = append(*pq, x.(pqItem)) }
+func (pq *priorityQueue) Pop() interface{} {
+	old := *pq
+	item := old[len(old)-1]
+	*pq = old[:len(old)-1]
+	return item
+}
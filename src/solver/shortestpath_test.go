@@ -0,0 +1,129 @@
+package solver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// negativeGraph: 0→1→2→3 の一方通行の道 (1→2 は負の重み) と、迂回路 0→3
+func negativeGraph() GraphData {
+	return GraphData{
+		Nodes: []Node{{ID: 0}, {ID: 1, X: 1}, {ID: 2, X: 2}, {ID: 3, X: 3}},
+		Edges: []Edge{
+			{From: 0, To: 1, Weight: 1, Directed: true},
+			{From: 1, To: 2, Weight: -2, Directed: true},
+			{From: 2, To: 3, Weight: 1, Directed: true},
+			{From: 0, To: 3, Weight: 5, Directed: true},
+		},
+	}
+}
+
+func TestBellmanFordNegativeWeight(t *testing.T) {
+	aco, err := NewACOFromGraph(negativeGraph(), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := aco.SolveBellmanFord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Dist != 0 || len(result.Path) != 4 {
+		t.Fatalf("got dist %v path %v, want 0 via [0 1 2 3]", result.Dist, result.Path)
+	}
+
+	// 負の重みは ACO・Dijkstra・A* では扱えない
+	if _, err := aco.SolveDijkstra(); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("SolveDijkstra: got %v, want ErrInvalidGraph", err)
+	}
+	if _, err := aco.SolveAStar("euclidean"); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("SolveAStar: got %v, want ErrInvalidGraph", err)
+	}
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	graph := negativeGraph()
+	graph.Edges = append(graph.Edges, Edge{From: 2, To: 1, Weight: 1, Directed: true}) // 1→2→1 = -1
+	aco, err := NewACOFromGraph(graph, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := aco.SolveBellmanFord(); !errors.Is(err, ErrNegativeCycle) {
+		t.Fatalf("got %v, want ErrNegativeCycle", err)
+	}
+
+	// 重みの変更で閉路を正にすれば解ける
+	if err := aco.SetEdgeWeight(2, 1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := aco.SolveBellmanFord(); err != nil {
+		t.Fatal(err)
+	}
+	// 元の重みに戻すと再び負の閉路になる
+	if err := aco.SetEdgeWeight(2, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := aco.SolveBellmanFord(); !errors.Is(err, ErrNegativeCycle) {
+		t.Fatalf("after SetEdgeWeight: got %v, want ErrNegativeCycle", err)
+	}
+}
+
+func TestNegativeWeightNeedsDirectedEdge(t *testing.T) {
+	graph := negativeGraph()
+	graph.Edges[1].Directed = false
+	if _, err := NewACOFromGraph(graph, DefaultConfig()); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("undirected negative edge: got %v, want ErrInvalidGraph", err)
+	}
+
+	aco := NewACO(10, DefaultConfig())
+	e := aco.Graph.Edges[0]
+	if err := aco.SetEdgeWeight(e.From, e.To, -1); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("SetEdgeWeight on undirected edge: got %v, want ErrInvalidGraph", err)
+	}
+	if err := aco.SetEdgeWeight(e.From, e.To, 0); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("SetEdgeWeight to 0: got %v, want ErrInvalidGraph", err)
+	}
+}
+
+// 負の閉路があると Dijkstra 系のループが終わらないので、Bellman-Ford 以外は探索の前にエラーにする
+func TestNegativeCycleRejectedBySearch(t *testing.T) {
+	graph := negativeGraph()
+	graph.Edges = append(graph.Edges, Edge{From: 2, To: 1, Weight: 1, Directed: true})
+	cfg := DefaultConfig()
+	cfg.Mode = ModeOrienteering // Step ごとにゴールまでの距離を Dijkstra で求める
+	cfg.Budget = 10
+	aco, err := NewACOFromGraph(graph, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ants := aco.Step(); ants != nil || !errors.Is(aco.StepError(), ErrInvalidGraph) || aco.Iteration != 0 {
+		t.Fatalf("Step: got %d ants, error %v, iteration %d; want no step and ErrInvalidGraph", len(ants), aco.StepError(), aco.Iteration)
+	}
+	if _, err := aco.Run(3); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("Run: got %v, want ErrInvalidGraph", err)
+	}
+	if _, err := aco.RunFor(time.Millisecond, 0, ProgressHook{}); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("RunFor: got %v, want ErrInvalidGraph", err)
+	}
+	if _, err := aco.Sweep(DefaultSweepConfig()); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("Sweep: got %v, want ErrInvalidGraph", err)
+	}
+	if _, err := aco.EdgeCentrality(); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("EdgeCentrality: got %v, want ErrInvalidGraph", err)
+	}
+	if _, err := aco.PheromoneRoutes(nil, nil, ExtractDijkstra); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("PheromoneRoutes: got %v, want ErrInvalidGraph", err)
+	}
+	if _, err := aco.SolveBaseline(BaselineGreedy, DefaultBaselineConfig()); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("SolveBaseline: got %v, want ErrInvalidGraph", err)
+	}
+
+	// 閉路を正に戻せば再び進められる
+	if err := aco.SetEdgeWeight(1, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if aco.Step(); aco.StepError() != nil || aco.Iteration != 1 {
+		t.Fatalf("after SetEdgeWeight: error %v, iteration %d", aco.StepError(), aco.Iteration)
+	}
+}
@@ -0,0 +1,183 @@
+package solver
+
+import "fmt"
+
+// スナップショット (saveState / loadState)
+// 続きから再開できるよう、グラフ・フェロモン・ベスト・乱数の状態・カウンタを丸ごと保存する。
+// 同じスナップショットから復元すれば、以降の Step は保存しなかった場合と同じ結果になる。
+
+// SnapshotVersion: Snapshot の形式バージョン (互換性のない変更をしたら上げる)
+const SnapshotVersion = 1
+
+// Snapshot: インスタンスの完全な状態
+type Snapshot struct {
+	Version    int            `json:"version"`
+	Config     Config         `json:"config"`
+	Graph      GraphData      `json:"graph"`
+	Pheromones []float64      `json:"pheromones"`        // Graph.Edges と同順
+	Usage      []int          `json:"usage,omitempty"`   // 混雑モードでの辺ごとの通過数 (Graph.Edges と同順)
+	Traffic    []int          `json:"traffic,omitempty"` // 辺ごとの通過数の累計 (Graph.Edges と同順)
+	StartNode  int            `json:"start"`
+	GoalNode   int            `json:"goal"`
+	Goals      []int          `json:"goals,omitempty"`
+	Waypoints  []int          `json:"waypoints,omitempty"`
+	BestDist   float64        `json:"bestDist"`
+	BestPath   []int          `json:"bestPath"`
+	BestPrize  float64        `json:"bestPrize,omitempty"`
+	TopPaths   []RankedPath   `json:"topPaths,omitempty"`
+	Pareto     []ParetoPath   `json:"pareto,omitempty"`
+	History    []Improvement  `json:"history,omitempty"`
+	Seed       int64          `json:"seed"`
+	RNG        []byte         `json:"rng"`
+	Paused     bool           `json:"paused"`
+	Iteration  int            `json:"iteration"`
+	Stagnation int            `json:"stagnation"`
+	Adaptive   AdaptiveLevels `json:"adaptive"`
+	Stats      IterationStats `json:"stats"`
+	Visits     []int          `json:"visits,omitempty"` // ノードごとの訪問回数の累計
+	Colonies   []Snapshot     `json:"colonies,omitempty"`
+}
+
+// Snapshot: 現在の状態を保存する
+func (aco *ACO) Snapshot() (Snapshot, error) {
+	rng, err := aco.randState.MarshalBinary()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	pheromones := make([]float64, len(aco.Graph.Edges))
+	traffic := make([]int, len(aco.Graph.Edges))
+	var usage []int
+	if aco.Config.Congestion > 0 {
+		usage = make([]int, len(aco.Graph.Edges))
+	}
+	for i, e := range aco.Graph.Edges {
+		pheromones[i] = aco.pheromone(e.From, e.To)
+		if nb := aco.neighbor(e.From, e.To); nb != nil {
+			traffic[i] = nb.Traffic
+		}
+		if usage != nil {
+			usage[i] = aco.neighbor(e.From, e.To).Usage
+		}
+	}
+
+	snapshot := Snapshot{
+		Version: SnapshotVersion,
+		Config:  aco.Config,
+		Graph: GraphData{
+			Nodes:     append([]Node(nil), aco.Graph.Nodes...),
+			Edges:     append([]Edge(nil), aco.Graph.Edges...),
+			Mode:      aco.Graph.Mode,
+			Is3D:      aco.Graph.Is3D,
+			Torus:     aco.Graph.Torus,
+			Obstacles: aco.Graph.Obstacles,
+		},
+		Pheromones: pheromones,
+		Usage:      usage,
+		Traffic:    traffic,
+		StartNode:  aco.StartNode,
+		GoalNode:   aco.GoalNode,
+		Goals:      append([]int(nil), aco.Goals...),
+		Waypoints:  append([]int(nil), aco.Waypoints...),
+		BestDist:   aco.BestDist,
+		BestPath:   append([]int(nil), aco.BestPath...),
+		BestPrize:  aco.BestPrize,
+		TopPaths:   append([]RankedPath(nil), aco.TopPaths...),
+		Pareto:     append([]ParetoPath(nil), aco.ParetoFront...),
+		History:    append([]Improvement(nil), aco.History...),
+		Seed:       aco.Seed,
+		RNG:        rng,
+		Paused:     aco.Paused,
+		Iteration:  aco.Iteration,
+		Stagnation: aco.Stagnation,
+		Adaptive:   aco.Adaptive,
+		Stats:      aco.Stats,
+		Visits:     append([]int(nil), aco.totalVisits...),
+	}
+	for _, colony := range aco.Colonies {
+		colonySnapshot, err := colony.Snapshot()
+		if err != nil {
+			return Snapshot{}, err
+		}
+		snapshot.Colonies = append(snapshot.Colonies, colonySnapshot)
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot: スナップショットからインスタンスを復元する
+func RestoreSnapshot(s Snapshot) (*ACO, error) {
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported snapshot version %d (expected %d)", ErrInvalidConfig, s.Version, SnapshotVersion)
+	}
+	// 乱数源を選べるようになる前のスナップショットは PCG
+	if s.Config.RNG == "" {
+		s.Config.RNG = RNGPCG
+	}
+	if err := s.Config.Validate(); err != nil {
+		return nil, err
+	}
+	graph, err := normalizeGraph(s.Graph, s.Config)
+	if err != nil {
+		return nil, err
+	}
+	if len(graph.Edges) != len(s.Graph.Edges) || len(s.Pheromones) != len(graph.Edges) {
+		return nil, fmt.Errorf("%w: snapshot has %d edges but %d pheromone values", ErrInvalidGraph, len(graph.Edges), len(s.Pheromones))
+	}
+	randSource, src, err := restoreRand(s.Config, s.RNG)
+	if err != nil {
+		return nil, fmt.Errorf("%w: restoring rng: %v", ErrInvalidConfig, err)
+	}
+
+	// コロニーは新しく作らず、保存されたものを復元する
+	cfg := s.Config
+	cfg.Colonies = 0
+	aco := newACO(graph, cfg, s.Seed, randSource, src)
+	aco.Config = s.Config
+
+	for i, e := range graph.Edges {
+		aco.setPheromone(e.From, e.To, s.Pheromones[i])
+	}
+	if len(s.Usage) == len(graph.Edges) {
+		for i, e := range graph.Edges {
+			aco.neighbor(e.From, e.To).Usage = s.Usage[i]
+			if rev := aco.neighbor(e.To, e.From); !e.Directed && rev != nil {
+				rev.Usage = s.Usage[i]
+			}
+		}
+		aco.refreshDistances()
+	}
+	if len(s.Traffic) == len(graph.Edges) {
+		for i, e := range graph.Edges {
+			aco.neighbor(e.From, e.To).Traffic = s.Traffic[i]
+			if rev := aco.neighbor(e.To, e.From); !e.Directed && rev != nil {
+				rev.Traffic = s.Traffic[i]
+			}
+		}
+	}
+	for _, id := range append(append([]int{s.StartNode, s.GoalNode}, s.Goals...), s.Waypoints...) {
+		if err := aco.checkNode(id); err != nil {
+			return nil, err
+		}
+	}
+	aco.StartNode, aco.GoalNode = s.StartNode, s.GoalNode
+	aco.Goals, aco.Waypoints = s.Goals, s.Waypoints
+	aco.BestDist, aco.BestPath, aco.TopPaths, aco.History = s.BestDist, s.BestPath, s.TopPaths, s.History
+	aco.BestPrize, aco.ParetoFront = s.BestPrize, s.Pareto
+	if len(aco.BestPath) == 0 {
+		aco.BestPath = nil
+	}
+	aco.Paused, aco.Iteration, aco.Stagnation, aco.Stats = s.Paused, s.Iteration, s.Stagnation, s.Stats
+	aco.Adaptive = s.Adaptive
+	if len(s.Visits) == len(graph.Nodes) {
+		aco.totalVisits = s.Visits
+	}
+
+	for _, colonySnapshot := range s.Colonies {
+		colony, err := RestoreSnapshot(colonySnapshot)
+		if err != nil {
+			return nil, err
+		}
+		aco.Colonies = append(aco.Colonies, colony)
+	}
+	return aco, nil
+}
@@ -0,0 +1,94 @@
+package solver
+
+import "math"
+
+// 座標空間 (ノードの座標から辺の長さ・直線距離・向きを求める)
+// 長さの測り方は Config.Metric で選ぶ (重みを省略した辺は、生成グラフでも読み込んだグラフでもこの長さから求める)。
+// Config.Torus なら生成グラフの座標空間を Width x Height (x Depth) の周期で折り返すトーラスにする。
+// 端の近くのノードは反対側の端のノードとも近くなるので、境界の影響を除いた空間ネットワークを調べられる。
+// 読み込んだグラフは GraphData.Torus を指定したときだけ、同じ周期で折り返す。
+
+// 座標上の長さの測り方 (Config.Metric)
+const (
+	MetricEuclidean = "euclidean" // 直線距離
+	MetricManhattan = "manhattan" // 軸ごとの差の絶対値の和 (格子状の街路向け)
+	MetricHaversine = "haversine" // X を経度・Y を緯度 (度) とみなした大円距離 (km、Z は使わない)
+)
+
+// EarthRadius: haversine で使う地球の半径 (km)
+const EarthRadius = 6371.0
+
+// space: 距離の測り方
+type space struct {
+	metric               string // 空なら euclidean
+	torus                bool
+	width, height, depth float64 // torus のときの各軸の周期 (depth が 0 なら Z は折り返さない)
+}
+
+// newSpace: cfg の測り方・大きさの座標空間 (torus なら折り返す)
+func newSpace(torus bool, cfg Config) space {
+	if !torus {
+		return space{metric: cfg.Metric}
+	}
+	return space{metric: cfg.Metric, torus: true, width: cfg.Width, height: cfg.Height, depth: cfg.Depth}
+}
+
+// space: インスタンスのグラフの座標空間
+func (aco *ACO) space() space {
+	return newSpace(aco.Graph.Torus, aco.Config)
+}
+
+// delta: a から b への座標の差 (トーラスでは折り返した方が近ければそちらの向き)
+func (s space) delta(a, b Node) (dx, dy, dz float64) {
+	dx, dy, dz = b.X-a.X, b.Y-a.Y, b.Z-a.Z
+	if s.torus {
+		dx, dy, dz = wrap(dx, s.width), wrap(dy, s.height), wrap(dz, s.depth)
+	}
+	return dx, dy, dz
+}
+
+// distance: 2つのノードの座標上の長さ (Z を含む。2次元のノードは Z が 0)
+func (s space) distance(a, b Node) float64 {
+	if s.metric == MetricHaversine {
+		return haversine(a.X, a.Y, b.X, b.Y)
+	}
+	dx, dy, dz := s.delta(a, b)
+	if s.metric == MetricManhattan {
+		return math.Abs(dx) + math.Abs(dy) + math.Abs(dz)
+	}
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// haversine: 経度・緯度 (度) で表した2点の大円距離 (km)
+func haversine(lon1, lat1, lon2, lat2 float64) float64 {
+	const rad = math.Pi / 180
+	sinLat := math.Sin((lat2 - lat1) * rad / 2)
+	sinLon := math.Sin((lon2 - lon1) * rad / 2)
+	h := sinLat*sinLat + math.Cos(lat1*rad)*math.Cos(lat2*rad)*sinLon*sinLon
+	return 2 * EarthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// wrap: 周期 period で折り返した差を [-period/2, period/2] に収める (period が 0 なら そのまま)
+func wrap(d, period float64) float64 {
+	if period <= 0 {
+		return d
+	}
+	d = math.Mod(d, period)
+	switch {
+	case d > period/2:
+		d -= period
+	case d < -period/2:
+		d += period
+	}
+	return d
+}
+
+// hasDepth: Z が 0 でないノードがあるか
+func hasDepth(nodes []Node) bool {
+	for _, node := range nodes {
+		if node.Z != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package solver
+
+import "fmt"
+
+// アリの種 (Config.Species)
+// 1つのコロニーのアリを α・β・q0 の異なる種に分ける (例: β が高く q0 が 0 の探索役と、q0 の高い活用役)。
+// 種で省略した値はコロニーの設定を使い、α・β には自動調整の倍率も掛ける。
+// アリは番号順に Share の比で種に割り当てるので、アリの数が同じなら毎イテレーション同じ番号のアリが同じ種になる。
+// フェロモンの散布はどの種も同じ規則で行う。
+
+// Species: アリの種
+type Species struct {
+	Name  string   `json:"name"`
+	Share float64  `json:"share"` // アリに占める割合 (全ての種の Share の合計に対する比)
+	Alpha *float64 `json:"alpha,omitempty"`
+	Beta  *float64 `json:"beta,omitempty"`
+	Q0    *float64 `json:"q0,omitempty"`
+}
+
+// antParams: アリ1匹が経路構築に使うパラメータ
+type antParams struct {
+	alpha, beta, q0 float64
+	species         string // 種の名前 (種がなければ空)
+}
+
+// validateSpecies: 名前が空でなく重複せず、割合と各パラメータが範囲内か
+func validateSpecies(species []Species) error {
+	names := map[string]bool{}
+	for _, s := range species {
+		if s.Name == "" || names[s.Name] {
+			return fmt.Errorf("%w: species names must be non-empty and unique (got %q)", ErrInvalidConfig, s.Name)
+		}
+		names[s.Name] = true
+		if s.Share <= 0 {
+			return fmt.Errorf("%w: species %q: share must be > 0 (got %g)", ErrInvalidConfig, s.Name, s.Share)
+		}
+		if s.Alpha != nil && *s.Alpha < 0 || s.Beta != nil && *s.Beta < 0 {
+			return fmt.Errorf("%w: species %q: alpha and beta must be >= 0", ErrInvalidConfig, s.Name)
+		}
+		if s.Q0 != nil && (*s.Q0 < 0 || *s.Q0 > 1) {
+			return fmt.Errorf("%w: species %q: q0 must be in [0, 1] (got %g)", ErrInvalidConfig, s.Name, *s.Q0)
+		}
+	}
+	return nil
+}
+
+// prepareAntParams: このイテレーションの各アリのパラメータを作業領域に入れる (constructAll から呼ぶ)
+func (aco *ACO) prepareAntParams(buffers []*antBuffer) {
+	base := antParams{alpha: aco.alpha(), beta: aco.beta(), q0: aco.Config.Q0}
+	species := aco.Config.Species
+	if len(species) == 0 {
+		for _, buf := range buffers {
+			buf.params = base
+		}
+		return
+	}
+
+	total := 0.0
+	for _, s := range species {
+		total += s.Share
+	}
+	i, cumulative := 0, species[0].Share
+	for k, buf := range buffers {
+		// アリ k の中央 (k + 0.5) / antCount が入る区間の種
+		for i < len(species)-1 && (float64(k)+0.5)/float64(len(buffers))*total > cumulative {
+			i++
+			cumulative += species[i].Share
+		}
+		s := species[i]
+		p := base
+		p.species = s.Name
+		if s.Alpha != nil {
+			p.alpha = *s.Alpha * aco.adaptiveScale(aco.Adaptive.Alpha)
+		}
+		if s.Beta != nil {
+			p.beta = *s.Beta * aco.adaptiveScale(aco.Adaptive.Beta)
+		}
+		if s.Q0 != nil {
+			p.q0 = *s.Q0
+		}
+		buf.params = p
+	}
+}
@@ -0,0 +1,188 @@
+package solver
+
+// λ-branching factor の既定の λ
+const BranchingLambda = 0.05
+
+// recordStats: 1イテレーション分の結果を統計に追加する
+func (aco *ACO) recordStats(antResults []AntResult) {
+	successes, deadEnds, stepLimited, overBudget, backtracks := 0, 0, 0, 0, 0
+	totalDist, totalHops := 0.0, 0.0
+	aco.stuckCounts = resize(aco.stuckCounts, len(aco.Graph.Nodes))
+	stuckNode := -1
+	for _, result := range antResults {
+		if result.DeadEnd && len(result.Path) > 0 {
+			deadEnds++
+			node := result.Path[len(result.Path)-1]
+			aco.stuckCounts[node]++
+			// 同数なら ID の小さいノード
+			if stuckNode == -1 || aco.stuckCounts[node] > aco.stuckCounts[stuckNode] ||
+				aco.stuckCounts[node] == aco.stuckCounts[stuckNode] && node < stuckNode {
+				stuckNode = node
+			}
+		}
+		if result.StepLimit {
+			stepLimited++
+		}
+		if result.OverBudget {
+			overBudget++
+		}
+		backtracks += result.Backtracks
+		if !result.Success {
+			continue
+		}
+		successes++
+		totalDist += result.Dist
+		totalHops += float64(len(result.Path))
+	}
+
+	avgDist, avgHops := 0.0, 0.0
+	if successes > 0 {
+		avgDist = totalDist / float64(successes)
+		avgHops = totalHops / float64(successes)
+	}
+
+	s := &aco.Stats
+	s.BestHistory = append(s.BestHistory, aco.BestDist)
+	s.SuccessRate = append(s.SuccessRate, float64(successes)/float64(len(antResults)))
+	s.AvgDist = append(s.AvgDist, avgDist)
+	s.AvgHops = append(s.AvgHops, avgHops)
+	s.Entropy = append(s.Entropy, aco.PheromoneEntropy())
+	s.Branching = append(s.Branching, aco.BranchingFactor(BranchingLambda))
+	s.Diversity = append(s.Diversity, aco.PathDiversity(antResults))
+	s.DeadEnds = append(s.DeadEnds, deadEnds)
+	s.StuckNodes = append(s.StuckNodes, stuckNode)
+	s.StepLimited = append(s.StepLimited, stepLimited)
+	s.OverBudget = append(s.OverBudget, overBudget)
+	s.Backtracks = append(s.Backtracks, backtracks)
+
+	aco.countVisits(antResults)
+	aco.recordFrame()
+}
+
+// Failures: 直近の Step でゴールできなかったアリの内訳
+type Failures struct {
+	DeadEnds    int `json:"deadEnds"`    // 行き止まりで進めなくなった
+	StepLimited int `json:"stepLimited"` // ステップ数の上限 (Config.MaxSteps) で打ち切られた
+	OverBudget  int `json:"overBudget"`  // 距離の予算 (Config.Budget) を超えて打ち切られた
+	StuckNode   int `json:"stuckNode"`   // 行き止まりで止まったアリが最も多かったノード (なければ -1)
+}
+
+// LastFailures: 直近の Step の Failures (Step 前はすべて 0、StuckNode は -1)
+func (aco *ACO) LastFailures() Failures {
+	s := aco.Stats
+	if len(s.DeadEnds) == 0 {
+		return Failures{StuckNode: -1}
+	}
+	// 各系列の末尾が直近の Step (診断を記録する前のスナップショットから復元すると長さが揃わない)
+	return Failures{
+		DeadEnds:    s.DeadEnds[len(s.DeadEnds)-1],
+		StepLimited: s.StepLimited[len(s.StepLimited)-1],
+		OverBudget:  s.OverBudget[len(s.OverBudget)-1],
+		StuckNode:   s.StuckNodes[len(s.StuckNodes)-1],
+	}
+}
+
+// StepError: 直近の Step を進められなかった理由 (進めたなら nil)
+// 正でない距離の辺があると Step はイテレーションを進めずに nil を返す
+func (aco *ACO) StepError() error {
+	return aco.stepErr
+}
+
+// PheromoneSummary: 全ての辺のフェロモン量の最小・最大・平均
+func (aco *ACO) PheromoneSummary() PheromoneSummary {
+	if len(aco.Graph.Edges) == 0 {
+		return PheromoneSummary{}
+	}
+
+	first := aco.Graph.Edges[0]
+	summary := PheromoneSummary{
+		Min: aco.pheromone(first.From, first.To),
+		Max: aco.pheromone(first.From, first.To),
+	}
+	total := 0.0
+	for _, e := range aco.Graph.Edges {
+		value := aco.pheromone(e.From, e.To)
+		if value < summary.Min {
+			summary.Min = value
+		}
+		if value > summary.Max {
+			summary.Max = value
+		}
+		total += value
+	}
+	summary.Mean = total / float64(len(aco.Graph.Edges))
+	return summary
+}
+
+// GetStats: 蓄積した統計と現在のフェロモン要約
+func (aco *ACO) GetStats() Stats {
+	return Stats{
+		Iteration:      aco.Iteration,
+		IterationStats: aco.Stats,
+		Pheromone:      aco.PheromoneSummary(),
+	}
+}
+
+// BranchingFactor: λ-branching factor (ノードごとに、出る辺のうちフェロモン量が
+// τmin + λ(τmax - τmin) 以上のものの数を数え、辺を持つノードで平均する)
+// 隣接ノード数の平均に近いほど探索的、1 に近いほど各ノードで行き先が決まっている (収束)
+func (aco *ACO) BranchingFactor(lambda float64) float64 {
+	total, nodes := 0, 0
+	for _, neighbors := range aco.Adj {
+		if len(neighbors) == 0 {
+			continue
+		}
+		tauMin, tauMax := neighbors[0].Pheromone, neighbors[0].Pheromone
+		for _, nb := range neighbors[1:] {
+			tauMin = min(tauMin, nb.Pheromone)
+			tauMax = max(tauMax, nb.Pheromone)
+		}
+		threshold := tauMin + lambda*(tauMax-tauMin)
+		for _, nb := range neighbors {
+			if nb.Pheromone >= threshold {
+				total++
+			}
+		}
+		nodes++
+	}
+	if nodes == 0 {
+		return 0
+	}
+	return float64(total) / float64(nodes)
+}
+
+// PathDiversity: このイテレーションのアリの経路の多様性 (ゴールできなかったアリも含む)
+// 1 - (2匹の経路が共有する辺の数の平均 / 経路の辺の数の平均)。全員が同じ経路なら 0、
+// 辺を1本も共有しなければ 1。辺ごとの通過数 (countUsage 済みの Usage) から O(E) で求める
+func (aco *ACO) PathDiversity(antResults []AntResult) float64 {
+	ants := 0
+	for _, result := range antResults {
+		if len(result.Path) > 1 {
+			ants++
+		}
+	}
+	if ants < 2 {
+		return 0
+	}
+
+	// 通過数はコロニーごとに持つので合算する (無向辺の2つの半辺は同じ数を持つので片方だけ見る)
+	sources := aco.Colonies
+	if len(sources) == 0 {
+		sources = []*ACO{aco}
+	}
+	traversals, sharedPairs := 0.0, 0.0
+	for _, e := range aco.Graph.Edges {
+		count := 0
+		for _, source := range sources {
+			if nb := source.neighbor(e.From, e.To); nb != nil {
+				count += nb.Usage
+			}
+		}
+		traversals += float64(count)
+		sharedPairs += float64(count * (count - 1))
+	}
+	if traversals == 0 {
+		return 0
+	}
+	return 1 - sharedPairs/(float64(ants-1)*traversals)
+}
@@ -0,0 +1,113 @@
+package solver
+
+import "fmt"
+
+// パラメータスイープ (α・β・蒸発率の格子を総当たりし、感度のヒートマップ用の表を作る)
+// 各組み合わせはインスタンスの複製 (同じグラフ・ルート・乱数の状態) で動かすので、
+// 違いはパラメータだけから生じ、元のインスタンスにも影響しない。
+
+// スイープの既定値
+const (
+	SweepIterations = 50   // 1つの組み合わせで回すイテレーション数
+	SweepMaxCells   = 1000 // 組み合わせ数の上限
+)
+
+// SweepConfig: Sweep のパラメータ (空の軸は現在の設定値1つだけを使う)
+type SweepConfig struct {
+	Iterations  int       `json:"iterations"`
+	Alpha       []float64 `json:"alpha"`
+	Beta        []float64 `json:"beta"`
+	Evaporation []float64 `json:"evaporation"`
+}
+
+func DefaultSweepConfig() SweepConfig {
+	return SweepConfig{
+		Iterations:  SweepIterations,
+		Alpha:       []float64{0.5, 1, 2},
+		Beta:        []float64{1, 2, 3, 5},
+		Evaporation: []float64{0.1, 0.3, 0.5},
+	}
+}
+
+// SweepCell: 最も良かった組み合わせ
+type SweepCell struct {
+	Alpha       float64 `json:"alpha"`
+	Beta        float64 `json:"beta"`
+	Evaporation float64 `json:"evaporation"`
+	Dist        float64 `json:"dist"`
+}
+
+// SweepResult: Sweep の結果
+// BestDist[a][b][e] は Alpha[a]・Beta[b]・Evaporation[e] で得たベスト距離 (ゴールできなければ nil)
+type SweepResult struct {
+	Iterations  int            `json:"iterations"`
+	Seed        int64          `json:"seed"`
+	Alpha       []float64      `json:"alpha"`
+	Beta        []float64      `json:"beta"`
+	Evaporation []float64      `json:"evaporation"`
+	BestDist    [][][]*float64 `json:"bestDist"`
+	Best        *SweepCell     `json:"best"`
+}
+
+// Sweep: α・β・蒸発率の全ての組み合わせで Iterations 回ずつ回し、最終的なベスト距離を並べる
+func (aco *ACO) Sweep(cfg SweepConfig) (SweepResult, error) {
+	if cfg.Iterations < 1 {
+		return SweepResult{}, fmt.Errorf("%w: iterations must be >= 1 (got %d)", ErrInvalidConfig, cfg.Iterations)
+	}
+	axes := [][]float64{cfg.Alpha, cfg.Beta, cfg.Evaporation}
+	current := []float64{aco.Config.Alpha, aco.Config.Beta, aco.Config.Evaporation}
+	cells := 1
+	for i := range axes {
+		if len(axes[i]) == 0 {
+			axes[i] = current[i : i+1]
+		}
+		cells *= len(axes[i])
+	}
+	if cells > SweepMaxCells {
+		return SweepResult{}, fmt.Errorf("%w: sweep has %d combinations (max %d)", ErrInvalidConfig, cells, SweepMaxCells)
+	}
+	snapshot, err := aco.Snapshot()
+	if err != nil {
+		return SweepResult{}, err
+	}
+
+	result := SweepResult{
+		Iterations:  cfg.Iterations,
+		Seed:        aco.Seed,
+		Alpha:       axes[0],
+		Beta:        axes[1],
+		Evaporation: axes[2],
+		BestDist:    make([][][]*float64, len(axes[0])),
+	}
+	for a, alpha := range result.Alpha {
+		result.BestDist[a] = make([][]*float64, len(result.Beta))
+		for b, beta := range result.Beta {
+			result.BestDist[a][b] = make([]*float64, len(result.Evaporation))
+			for e, evaporation := range result.Evaporation {
+				instance, err := RestoreSnapshot(snapshot)
+				if err != nil {
+					return SweepResult{}, err
+				}
+				instance.Reset(true)
+				params := instance.Config
+				params.Alpha, params.Beta, params.Evaporation = alpha, beta, evaporation
+				if err := instance.SetParams(params); err != nil {
+					return SweepResult{}, err
+				}
+
+				if _, err := instance.Run(cfg.Iterations); err != nil {
+					return SweepResult{}, err
+				}
+				if instance.BestPath == nil {
+					continue
+				}
+				dist := instance.BestDist
+				result.BestDist[a][b][e] = &dist
+				if result.Best == nil || dist < result.Best.Dist {
+					result.Best = &SweepCell{Alpha: alpha, Beta: beta, Evaporation: evaporation, Dist: dist}
+				}
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package solver
+
+import "sort"
+
+// 上位K経路 (Config.TopK)
+// これまでに見つかった互いに異なる経路のうち短い順に K 本を保持する (代替ルートの表示用)。
+
+// RankedPath: 上位K経路の1本
+type RankedPath struct {
+	Dist float64 `json:"dist"`
+	Path []int   `json:"path"`
+}
+
+// recordTopPath: path が上位K本に入るなら (重複を除いて) 追加する
+func (aco *ACO) recordTopPath(path []int, dist float64) {
+	k := aco.Config.TopK
+	if k <= 0 {
+		return
+	}
+	if len(aco.TopPaths) == k && dist >= aco.TopPaths[k-1].Dist {
+		return
+	}
+	for _, ranked := range aco.TopPaths {
+		if aco.samePath(ranked.Path, path) {
+			return
+		}
+	}
+
+	i := sort.Search(len(aco.TopPaths), func(i int) bool { return aco.TopPaths[i].Dist > dist })
+	aco.TopPaths = append(aco.TopPaths, RankedPath{})
+	copy(aco.TopPaths[i+1:], aco.TopPaths[i:])
+	aco.TopPaths[i] = RankedPath{Dist: dist, Path: append([]int(nil), path...)}
+	if len(aco.TopPaths) > k {
+		aco.TopPaths = aco.TopPaths[:k]
+	}
+}
+
+// samePath: 同じ経路か (一方通行のない TSP では逆回りの巡回も同じとみなす)
+func (aco *ACO) samePath(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	forward := true
+	for i := range a {
+		if a[i] != b[i] {
+			forward = false
+			break
+		}
+	}
+	if forward || aco.Config.Mode != ModeTSP || aco.hasOneWayEdges() || len(a) == 0 {
+		return forward
+	}
+	// 始点は共通なので、残りを逆順に比べる
+	for i := 1; i < len(a); i++ {
+		if a[i] != b[len(b)-i] {
+			return false
+		}
+	}
+	return a[0] == b[0]
+}
+
+// filterTopPaths: keep が false を返す経路を上位K本から外す
+func (aco *ACO) filterTopPaths(keep func(path []int) bool) {
+	kept := aco.TopPaths[:0]
+	for _, ranked := range aco.TopPaths {
+		if keep(ranked.Path) {
+			kept = append(kept, ranked)
+		}
+	}
+	aco.TopPaths = kept
+}
+
+// rescoreTopPaths: 重みの変更後に距離を計算し直して並べ替える
+func (aco *ACO) rescoreTopPaths() {
+	for i := range aco.TopPaths {
+		aco.TopPaths[i].Dist = aco.calculatePathDistance(aco.TopPaths[i].Path)
+	}
+	sort.SliceStable(aco.TopPaths, func(i, j int) bool { return aco.TopPaths[i].Dist < aco.TopPaths[j].Dist })
+}
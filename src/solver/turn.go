@@ -0,0 +1,42 @@
+package solver
+
+import "math"
+
+// 曲がり角のペナルティ (Config.TurnPenalty > 0)
+// ノードの座標から連続する2辺のなす角を求め、向きを変えるほど距離を上乗せする。
+// 直進は 0、真後ろへの折り返しで TurnPenalty になるので、滑らかな経路が好まれる (ロボットの経路計画向け)。
+
+// turnCost: prev→current→next と進むときの曲がるコスト (prev < 0 なら 0)
+func (aco *ACO) turnCost(prev, current, next int) float64 {
+	if aco.Config.TurnPenalty == 0 || prev < 0 {
+		return 0
+	}
+	a, b, c := aco.Graph.Nodes[prev], aco.Graph.Nodes[current], aco.Graph.Nodes[next]
+	space := aco.space()
+	x1, y1, z1 := space.delta(a, b)
+	x2, y2, z2 := space.delta(b, c)
+	if (x1 == 0 && y1 == 0 && z1 == 0) || (x2 == 0 && y2 == 0 && z2 == 0) {
+		return 0
+	}
+	// 2辺のなす角 = atan2(|外積|, 内積) (3次元でも同じ)
+	cx, cy, cz := y1*z2-z1*y2, z1*x2-x1*z2, x1*y2-y1*x2
+	angle := math.Atan2(math.Sqrt(cx*cx+cy*cy+cz*cz), x1*x2+y1*y2+z1*z2)
+	return aco.Config.TurnPenalty * angle / math.Pi
+}
+
+// pathTurnCost: 経路の曲がり角のコストの合計 (TSP ではスタートに戻って一周した分も含む)
+func (aco *ACO) pathTurnCost(path []int) float64 {
+	if aco.Config.TurnPenalty == 0 {
+		return 0
+	}
+	n := len(path)
+	cost := 0.0
+	for i := 1; i < n-1; i++ {
+		cost += aco.turnCost(path[i-1], path[i], path[i+1])
+	}
+	if aco.Config.Mode == ModeTSP && n > 2 {
+		cost += aco.turnCost(path[n-2], path[n-1], path[0])
+		cost += aco.turnCost(path[n-1], path[0], path[1])
+	}
+	return cost
+}
@@ -0,0 +1,607 @@
+// Package solver: ACO ソルバ本体。syscall/js に依存しないのでネイティブでもビルド・実行できる。
+// ブラウザ向けのバインディングはリポジトリ直下の main パッケージ (js/wasm) にある。
+package solver
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// デフォルトのハイパーパラメータ (Config未指定時に使用)
+const (
+	AntCount         = 20
+	Alpha            = 1.0
+	Beta             = 5.0
+	Evaporation      = 0.5
+	Q                = 100.0
+	InitialPheromone = 1.0
+)
+
+// 経路構築の方式 (Config.Construction、経路探索モードのみ。TSP は常に simple)
+const (
+	ConstructionSimple      = "simple"       // 訪問済みのノードには入らず、行き止まりで失敗する
+	ConstructionBacktrack   = "backtrack"    // 行き止まりで1つ前のノードへ引き返す
+	ConstructionLoopErasure = "loop-erasure" // 行き止まりでは訪問済みのノードにも戻り、できた経路からループを取り除く (AntNet)
+)
+
+// フェロモン更新規則 (Config.Variant)
+const (
+	VariantAS   = "as"   // 基本のAnt System: 成功した全アリが散布
+	VariantRank = "rank" // ASrank: 上位 RankWidth-1 匹と大域ベストのみ順位で重み付けして散布
+)
+
+// 問題の種類 (Config.Mode)
+const (
+	ModeRoute = "route" // スタートからゴールへの経路探索
+	ModeTSP   = "tsp"   // 全ノードを巡回してスタートに戻る巡回セールスマン問題
+	// 距離の予算 (Config.Budget) の中で、スタートからゴールへ向かいながらノードの賞金を最も多く集める
+	ModeOrienteering = "orienteering"
+)
+
+// ASrank の既定の w
+const RankWidth = 6
+
+// コロニー間交換の既定の間隔
+const ExchangeInterval = 10
+
+// 収束とみなす既定の停滞イテレーション数
+const StagnationLimit = 100
+
+// 既定の座標空間の大きさ (Config.Width / Config.Height)
+const (
+	Width  = 100.0
+	Height = 100.0
+)
+
+// 座標上の長さから重みを求めるときの正規化方法 (Config.Normalization)
+const (
+	NormalizeExtent = "extent" // ノードの外接矩形の対角線で割る (重みはおおむね 0-1)
+	NormalizeSpace  = "space"  // 座標空間 (Width x Height) の対角線で割る
+	NormalizeNone   = "none"   // 座標上の長さをそのまま重みにする
+)
+
+// 重みの下限 (1/dist がゼロ除算にならないよう保証)
+const MinWeight = 0.0001
+
+type Node struct {
+	ID int     `json:"id"`
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+	// 3次元のグラフでの高さ (2次元なら 0)
+	Z float64 `json:"z,omitempty"`
+	// 表示用の名前・色 (都市名やルーター名など、読み込んだグラフの識別子を残す)
+	Label string `json:"label,omitempty"`
+	Color string `json:"color,omitempty"`
+	// 任意の付加情報 (ソルバーは使わずにそのまま返す)
+	Meta map[string]interface{} `json:"meta,omitempty"`
+	// 通過コスト (混雑した交差点など)。このノードに入るたびに経路の距離に加える
+	Cost float64 `json:"cost,omitempty"`
+	// オリエンテーリングモードで、このノードを通ると得られる賞金 (0 以上)
+	Prize float64 `json:"prize,omitempty"`
+}
+
+type Edge struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	// 重み (負の重みは一方通行の辺だけ。負の辺を含むグラフは SolveBellmanFord でしか解けない)
+	Weight float64 `json:"weight"`
+	// 座標上の実距離 (Weight は正規化後の値なので表示用に残す)
+	RawDist float64 `json:"rawDist"`
+	// 一方通行 (From→To のみ通れる)
+	Directed bool `json:"directed,omitempty"`
+	// 混雑モードでの容量 (1イテレーションに通るアリの数の目安、0 なら 1)
+	Capacity float64 `json:"capacity,omitempty"`
+	// 多目的で使う2つ目の重み (危険度など、0 以上)
+	Risk float64 `json:"risk,omitempty"`
+	// 連結成分をつなぐために自動で追加した辺 (Config.ConnectComponents)
+	Bridge bool `json:"bridge,omitempty"`
+}
+
+type GraphData struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+	// 問題の種類 (出力専用: インスタンスの Config.Mode を反映)
+	Mode string `json:"mode,omitempty"`
+	// ノードが Z 座標を持つ3次元のグラフか (出力専用: 生成時は Config.Depth > 0、読み込み時は Z が 0 でないノードがあるか)
+	Is3D bool `json:"is3D,omitempty"`
+	// 座標空間が Config の Width x Height (x Depth) で折り返すトーラスか (生成時は Config.Torus を反映)
+	Torus bool `json:"torus,omitempty"`
+	// 壁として描く障害物 (多角形)
+	Obstacles []Obstacle `json:"obstacles,omitempty"`
+	// 動的な編集のたびに増える版 (出力専用: GraphDiff に渡す)
+	Version int `json:"version"`
+}
+
+// Config: インスタンスごとのハイパーパラメータ
+type Config struct {
+	AntCount         int     `json:"antCount"`
+	Alpha            float64 `json:"alpha"`
+	Beta             float64 `json:"beta"`
+	Evaporation      float64 `json:"evaporation"`
+	Q                float64 `json:"q"`
+	InitialPheromone float64 `json:"initialPheromone"`
+	// アリ1匹が進める最大ステップ数 (0 で ノード数×2×区間数)
+	MaxSteps int `json:"maxSteps"`
+	// アリ1匹が進める距離の予算 (経路の距離と同じ尺度、0 で無制限)
+	// 途中で超えたアリはその場で失敗するので、ベスト経路は常に予算内に収まる
+	Budget float64 `json:"budget"`
+	// 蒸発率の時間変化 ("constant" | "linear" | "exponential" | "cosine")
+	EvaporationSchedule string `json:"evaporationSchedule"`
+	// スケジュールの終端での蒸発率と、そこに達するまでのイテレーション数
+	EvaporationEnd     float64 `json:"evaporationEnd"`
+	EvaporationHorizon int     `json:"evaporationHorizon"`
+	// フェロモン量の下限・上限 (毎回の更新後に適用、0で無効)
+	TauMin float64 `json:"tauMin"`
+	TauMax float64 `json:"tauMax"`
+	// この回数ごとに改善がなければフェロモンを初期値に戻す (0で無効)
+	RestartAfter int `json:"restartAfter"`
+	// リスタート時に大域ベスト経路の辺へ残すフェロモン量
+	RestartBias float64 `json:"restartBias"`
+	// 混雑の強さ: 前のイテレーションで辺を通ったアリの数 u に応じて重みを Weight * (1 + Congestion * u / Capacity) にする (0で無効)
+	Congestion float64 `json:"congestion"`
+	// 曲がり角のペナルティ: 経路が向きを θ 変えるごとに TurnPenalty * θ/π を距離に足す (0で無効)
+	// アリの距離とヒューリスティックにだけ効き、厳密解法 (Dijkstra など) は考慮しない
+	TurnPenalty float64 `json:"turnPenalty"`
+	// 多目的の重み付き和: 辺の距離に RiskWeight * Risk と HopWeight (1辺ごと) を足す (0で無効)
+	RiskWeight float64 `json:"riskWeight"`
+	HopWeight  float64 `json:"hopWeight"`
+	// 保持するパレート解 (距離・危険度・辺数で互いに優越しない経路) の最大数 (0で保持しない)
+	Pareto int `json:"pareto"`
+	// エリート戦略: 大域ベスト経路に ElitistWeight * Q / BestDist を追加散布 (0で無効)
+	ElitistWeight float64 `json:"elitistWeight"`
+	// 生成するネットワーク構造 ("ring" | "grid" | "delaunay" | "watts-strogatz" | "barabasi-albert")
+	Topology string `json:"topology"`
+	// 生成グラフの平均次数 (0でトポロジー既定。density より優先)
+	AverageDegree float64 `json:"averageDegree"`
+	// 生成グラフの密度 = 辺数 / 完全グラフの辺数 (0でトポロジー既定)
+	Density float64 `json:"density"`
+	// 生成グラフのノードを配置する座標空間の大きさ
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	// 生成グラフの Z 方向の大きさ (0 で2次元。障害物は XY 平面上の多角形を Z 方向に伸ばした柱とみなす)
+	Depth float64 `json:"depth"`
+	// 生成グラフの座標空間を端で折り返すトーラスにする (距離は反対側の端を回った方が近ければそちらで測る。
+	// grid は端どうしも辺で結ぶ。障害物とは併用できない)
+	Torus bool `json:"torus"`
+	// 生成グラフでノードを置かず、辺も横切らせない障害物 (長方形または多角形)
+	Obstacles []Obstacle `json:"obstacles,omitempty"`
+	// 重み省略時に辺の長さを座標から測る方法 ("euclidean" | "manhattan" | "haversine")
+	// haversine は X を経度・Y を緯度 (度) とみなして km で測る (トーラスとは併用できない)
+	Metric string `json:"metric"`
+	// 重み省略時に座標上の長さを重みへ換算する方法 ("extent" | "space" | "none")
+	Normalization string `json:"normalization"`
+	// 生成・読み込みの後、連結成分どうしを最も近いノードの組を結ぶ辺でつなぐ (追加した辺は Edge.Bridge)
+	ConnectComponents bool `json:"connectComponents"`
+	// 生成した辺のうち一方通行 (向きはランダム) にする割合
+	OneWayRatio float64 `json:"oneWayRatio"`
+	// 問題の種類 ("route" | "tsp" | "orienteering"。orienteering は Budget が必要)
+	Mode string `json:"mode"`
+	// フェロモン更新規則 ("as" | "rank")
+	Variant string `json:"variant"`
+	// ASrank で散布する順位数 w
+	RankWidth int `json:"rankWidth"`
+	// ゴールしたアリの経路を散布前に局所探索で改善する (TSPは2-opt、経路探索はショートカット)
+	LocalSearch bool `json:"localSearch"`
+	// 経路構築の方式 ("simple" | "backtrack" | "loop-erasure")
+	Construction string `json:"construction"`
+	// 確率 q0 で τ^α · η^β が最大の辺を選び、それ以外はルーレット選択する (0で常にルーレット)
+	Q0 float64 `json:"q0"`
+	// α・β・q0 の異なるアリの種 (空なら全てのアリが上の設定を使う)
+	Species []Species `json:"species,omitempty"`
+	// 停滞や成功率に応じて α・β・蒸発率を自動で調整する (Convergence に実際の値が出る)
+	Adaptive bool `json:"adaptive"`
+	// 辺の望ましさ η の計算方法 (RegisterHeuristic で登録した名前。HeuristicNames を参照)
+	Heuristic string `json:"heuristic"`
+	// 直近 k 個のノードだけを訪問済みとして避ける (0で区間内の全ノード。経路探索モードのみ)
+	// 古いノードへは戻れるようになるので、できた経路からループを取り除く
+	TabuLength int `json:"tabuLength"`
+	// 次ノードの選択を近い順 k 個の候補に絞る (0で全ての隣接ノード)
+	CandidateListSize int `json:"candidateListSize"`
+	// 保持する上位経路の本数 (0で保持しない)
+	TopK int `json:"topK"`
+	// 独立に走らせるコロニー数 (0, 1 は単一コロニー)
+	Colonies int `json:"colonies"`
+	// コロニー間で情報を交換する間隔 (イテレーション数、0で交換しない)
+	ExchangeInterval int `json:"exchangeInterval"`
+	// 交換方法 ("best" | "pheromone")
+	ExchangeMode string `json:"exchangeMode"`
+	// アリの経路構築を並列に行うゴルーチン数 (0, 1 は逐次)
+	Workers int `json:"workers"`
+	// この回数だけ改善がなければ収束とみなす (0で判定しない)
+	StagnationLimit int `json:"stagnationLimit"`
+	// 記録モード: 直近この回数のイテレーションの状態を残す (0で記録しない、GetReplay で取り出す)
+	Record int `json:"record"`
+	// 記録にフェロモン量の要約を含める
+	RecordPheromones bool `json:"recordPheromones"`
+	// 探索に使う乱数源 ("pcg" | "counter" | "crypto"。グラフの生成は常にシードからの PCG)
+	RNG string `json:"rng"`
+	// RNG が "crypto" のときに乱数のバイト列を埋める関数 (nil なら crypto/rand。保存されない)
+	Entropy func(p []byte) `json:"-"`
+	// 乱数シード (省略時は時刻から生成し、ACO.Seed に記録する)
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// AntResult: 1匹のアリの1イテレーション分の結果
+// Path はアリごとの作業領域を指し、次の Step で上書きされる (残す場合は複製する)
+type AntResult struct {
+	Path    []int   `json:"path"`
+	Dist    float64 `json:"dist"`
+	Success bool    `json:"success"`           // ゴールできたか？
+	Colony  int     `json:"colony,omitempty"`  // 所属コロニー (マルチコロニー時)
+	Species string  `json:"species,omitempty"` // アリの種の名前 (Config.Species があるとき)
+	// 集めた賞金 (オリエンテーリングモードで成功したアリのみ)
+	Prize float64 `json:"prize,omitempty"`
+	// 行き止まり (未訪問の隣接ノードがない) で進めなくなった
+	DeadEnd bool `json:"deadEnd,omitempty"`
+	// ステップ数の上限 (Config.MaxSteps) で打ち切られた
+	StepLimit bool `json:"stepLimit,omitempty"`
+	// 距離の予算 (Config.Budget) を超えて打ち切られた
+	OverBudget bool `json:"overBudget,omitempty"`
+	// 行き止まりから引き返した回数 (Config.Construction が "backtrack" のとき)
+	Backtracks int `json:"backtracks,omitempty"`
+}
+
+// Neighbor: 隣接リストの要素 (ノードから To への半辺)
+type Neighbor struct {
+	To        int
+	Dist      float64 // 辺の重み (混雑込み) + To の通過コスト
+	Pheromone float64
+	Risk      float64 // 辺の Risk (パレート解の目的値用)
+	Usage     int     // 前のイテレーションでこの辺を通ったアリの数 (無向辺は両向きの合計)
+	Traffic   int     // これまでにこの辺を通ったアリの数の累計 (Reset で 0 に戻る)
+	OneWay    bool    // 逆向きの半辺を持たない一方通行の辺
+}
+
+type ACO struct {
+	Config Config
+	Graph  GraphData
+	// 隣接リスト (Adj[u] は u から出る半辺。無向辺は両端に1つずつ)
+	Adj      [][]Neighbor
+	BestDist float64
+	BestPath []int
+	// オリエンテーリングモードでベスト経路が集めた賞金 (予算内で賞金が最大、同じなら短い経路がベスト)
+	BestPrize float64
+	Rand      *rand.Rand
+	Seed      int64
+	// これまでに見つかった互いに異なる経路の上位 Config.TopK 本 (短い順)
+	TopPaths []RankedPath
+	// これまでに見つかったパレート解: 距離・危険度・ホップ数の全てで負ける経路がないもの (距離の短い順、最大 Config.Pareto 本)
+	ParetoFront []ParetoPath
+	// ベスト経路が改善するたびの記録 (古い順)
+	History []Improvement
+	// Rand の状態 (スナップショット用)
+	randState randomSource
+	// 経路構築用のワーカーごとの乱数源 (アリごとのストリームに切り替えて使い回す)
+	antRands []antRand
+	// 現在のイテレーションで使うヒューリスティック (constructAll で Config.Heuristic から作る)
+	heuristic HeuristicFunc
+	// オリエンテーリングモードで使う、各ノードからゴールまでの最短距離と η に足す賞金の下駄 (constructAll で求める)
+	toGoal     []float64
+	prizeFloor float64
+	StartNode  int
+	GoalNode   int
+	// 複数ゴール時のゴールの集合 (先頭が GoalNode、単一ゴールなら nil)
+	Goals []int
+	// 経由地 (スタートとゴールの間に順に通るノード)
+	Waypoints []int
+	// 一時停止中か (自動実行ループが参照する)
+	Paused bool
+	// 実行済みイテレーション数
+	Iteration int
+	// 最後にベストが改善してからのイテレーション数
+	Stagnation int
+	// 自動調整 (Config.Adaptive) の現在の段階
+	Adaptive AdaptiveLevels
+	// マルチコロニー時の各コロニー (親はアリを走らせず結果をまとめる)
+	Colonies []*ACO
+	// StepGA で進める遺伝的アルゴリズムの状態 (最初の StepGA で作る)
+	ga *GA
+	// 読み込み時に取り除いた重複辺 (CheckGraph が報告する)
+	duplicateEdges [][2]int
+	// Delta で前回送った状態
+	delta deltaState
+	// 動的な編集の履歴 (GraphDiff が返す)
+	graphLog []GraphChange
+	// On で登録したイベントのハンドラ
+	handlers map[string][]func(Event)
+	// Step が使い回す作業領域 (アリの結果・構築結果・アリごとのバッファ)
+	antResults []AntResult
+	walks      []antWalk
+	antBuffers []*antBuffer
+	// 区間ごとの行き先 (constructAll がイテレーションの始めに求める)
+	stepTargets [][]int
+	// 記録モードのリングバッファ
+	replay replayBuffer
+	// Step ごとに蓄積する統計
+	Stats IterationStats
+	// 行き止まりで止まったアリのノードごとの数 (recordStats の作業領域)
+	stuckCounts []int
+	// ノードごとの訪問回数 (直近の Step と累計、VisitCounts を参照)
+	visits, totalVisits []int
+	// 直近の Step を進められなかった理由 (StepError を参照)
+	stepErr error
+	// 経路構築にかかった累計時間 (Benchmark が参照する)
+	constructTime time.Duration
+	// Dispose 済みか (以後は使用不可)
+	disposed bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		AntCount:            AntCount,
+		Alpha:               Alpha,
+		Beta:                Beta,
+		Evaporation:         Evaporation,
+		Q:                   Q,
+		InitialPheromone:    InitialPheromone,
+		EvaporationSchedule: ScheduleConstant,
+		EvaporationEnd:      EvaporationEnd,
+		EvaporationHorizon:  EvaporationHorizon,
+		Topology:            TopologyRing,
+		Width:               Width,
+		Height:              Height,
+		Normalization:       NormalizeExtent,
+		Metric:              MetricEuclidean,
+		Mode:                ModeRoute,
+		Variant:             VariantAS,
+		Construction:        ConstructionSimple,
+		Heuristic:           HeuristicInverseDistance,
+		RNG:                 RNGPCG,
+		RankWidth:           RankWidth,
+		ExchangeInterval:    ExchangeInterval,
+		ExchangeMode:        ExchangeBest,
+		StagnationLimit:     StagnationLimit,
+	}
+}
+
+// 乱数シードの上限 (JSの Number で正確に表現できる 2^53)
+const maxSafeSeed = 1 << 53
+
+// resolveSeed: 指定があればそのシードを、なければ時刻由来のシードを返す
+func (c Config) resolveSeed() int64 {
+	if c.Seed != nil {
+		return *c.Seed
+	}
+	return time.Now().UnixNano() % maxSafeSeed
+}
+
+// Validate: 探索が破綻する値を弾く
+func (c Config) Validate() error {
+	if c.AntCount < 1 {
+		return fmt.Errorf("%w: antCount must be >= 1 (got %d)", ErrInvalidConfig, c.AntCount)
+	}
+	if c.MaxSteps < 0 {
+		return fmt.Errorf("%w: maxSteps must be >= 0 (got %d)", ErrInvalidConfig, c.MaxSteps)
+	}
+	if c.Budget < 0 {
+		return fmt.Errorf("%w: budget must be >= 0 (got %g)", ErrInvalidConfig, c.Budget)
+	}
+	if c.TabuLength < 0 {
+		return fmt.Errorf("%w: tabuLength must be >= 0 (got %d)", ErrInvalidConfig, c.TabuLength)
+	}
+	if c.Q0 < 0 || c.Q0 > 1 {
+		return fmt.Errorf("%w: q0 must be in [0, 1] (got %g)", ErrInvalidConfig, c.Q0)
+	}
+	if c.Alpha < 0 || c.Beta < 0 {
+		return fmt.Errorf("%w: alpha and beta must be >= 0 (got %g, %g)", ErrInvalidConfig, c.Alpha, c.Beta)
+	}
+	if err := validateSpecies(c.Species); err != nil {
+		return err
+	}
+	if c.Evaporation < 0 || c.Evaporation > 1 {
+		return fmt.Errorf("%w: evaporation must be in [0, 1] (got %g)", ErrInvalidConfig, c.Evaporation)
+	}
+	if c.TauMin < 0 || c.TauMax < 0 {
+		return fmt.Errorf("%w: tauMin and tauMax must be >= 0 (got %g, %g)", ErrInvalidConfig, c.TauMin, c.TauMax)
+	}
+	if c.RestartAfter < 0 || c.RestartBias < 0 {
+		return fmt.Errorf("%w: restartAfter and restartBias must be >= 0 (got %d, %g)", ErrInvalidConfig, c.RestartAfter, c.RestartBias)
+	}
+	for _, o := range c.Obstacles {
+		if err := o.validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	}
+	if c.Congestion < 0 {
+		return fmt.Errorf("%w: congestion must be >= 0 (got %g)", ErrInvalidConfig, c.Congestion)
+	}
+	if c.TauMax > 0 && c.TauMin > c.TauMax {
+		return fmt.Errorf("%w: tauMin must be <= tauMax (got %g, %g)", ErrInvalidConfig, c.TauMin, c.TauMax)
+	}
+	switch c.EvaporationSchedule {
+	case ScheduleConstant:
+	case ScheduleLinear, ScheduleExponential, ScheduleCosine:
+		if c.EvaporationEnd < 0 || c.EvaporationEnd > 1 {
+			return fmt.Errorf("%w: evaporationEnd must be in [0, 1] (got %g)", ErrInvalidConfig, c.EvaporationEnd)
+		}
+		if c.EvaporationHorizon < 1 {
+			return fmt.Errorf("%w: evaporationHorizon must be >= 1 (got %d)", ErrInvalidConfig, c.EvaporationHorizon)
+		}
+		if c.EvaporationSchedule == ScheduleExponential && (c.Evaporation == 0 || c.EvaporationEnd == 0) {
+			return fmt.Errorf("%w: exponential schedule needs evaporation and evaporationEnd > 0 (got %g, %g)", ErrInvalidConfig, c.Evaporation, c.EvaporationEnd)
+		}
+	default:
+		return fmt.Errorf("%w: unknown evaporationSchedule %q (expected %q, %q, %q or %q)", ErrInvalidConfig, c.EvaporationSchedule, ScheduleConstant, ScheduleLinear, ScheduleExponential, ScheduleCosine)
+	}
+	if c.Q <= 0 {
+		return fmt.Errorf("%w: q must be > 0 (got %g)", ErrInvalidConfig, c.Q)
+	}
+	if c.InitialPheromone <= 0 {
+		return fmt.Errorf("%w: initialPheromone must be > 0 (got %g)", ErrInvalidConfig, c.InitialPheromone)
+	}
+	if c.ElitistWeight < 0 {
+		return fmt.Errorf("%w: elitistWeight must be >= 0 (got %g)", ErrInvalidConfig, c.ElitistWeight)
+	}
+	if err := validateTopology(c.Topology); err != nil {
+		return err
+	}
+	if c.AverageDegree < 0 {
+		return fmt.Errorf("%w: averageDegree must be >= 0 (got %g)", ErrInvalidConfig, c.AverageDegree)
+	}
+	if c.Density < 0 || c.Density > 1 {
+		return fmt.Errorf("%w: density must be in [0, 1] (got %g)", ErrInvalidConfig, c.Density)
+	}
+	if c.OneWayRatio < 0 || c.OneWayRatio > 1 {
+		return fmt.Errorf("%w: oneWayRatio must be in [0, 1] (got %g)", ErrInvalidConfig, c.OneWayRatio)
+	}
+	if c.Width <= 0 || c.Height <= 0 {
+		return fmt.Errorf("%w: width and height must be > 0 (got %g, %g)", ErrInvalidConfig, c.Width, c.Height)
+	}
+	if c.Depth < 0 {
+		return fmt.Errorf("%w: depth must be >= 0 (got %g)", ErrInvalidConfig, c.Depth)
+	}
+	if c.Torus && len(c.Obstacles) > 0 {
+		return fmt.Errorf("%w: torus cannot be combined with obstacles", ErrInvalidConfig)
+	}
+	switch c.Metric {
+	case MetricEuclidean, MetricManhattan:
+	case MetricHaversine:
+		if c.Torus {
+			return fmt.Errorf("%w: %q metric cannot be combined with torus", ErrInvalidConfig, MetricHaversine)
+		}
+	default:
+		return fmt.Errorf("%w: unknown metric %q (expected %q, %q or %q)", ErrInvalidConfig, c.Metric, MetricEuclidean, MetricManhattan, MetricHaversine)
+	}
+	if c.Normalization != NormalizeExtent && c.Normalization != NormalizeSpace && c.Normalization != NormalizeNone {
+		return fmt.Errorf("%w: unknown normalization %q (expected %q, %q or %q)", ErrInvalidConfig, c.Normalization, NormalizeExtent, NormalizeSpace, NormalizeNone)
+	}
+	switch c.Mode {
+	case ModeRoute, ModeTSP:
+	case ModeOrienteering:
+		if c.Budget <= 0 {
+			return fmt.Errorf("%w: %q mode needs a budget > 0", ErrInvalidConfig, ModeOrienteering)
+		}
+	default:
+		return fmt.Errorf("%w: unknown mode %q (expected %q, %q or %q)", ErrInvalidConfig, c.Mode, ModeRoute, ModeTSP, ModeOrienteering)
+	}
+	if c.CandidateListSize < 0 {
+		return fmt.Errorf("%w: candidateListSize must be >= 0 (got %d)", ErrInvalidConfig, c.CandidateListSize)
+	}
+	if c.TopK < 0 {
+		return fmt.Errorf("%w: topK must be >= 0 (got %d)", ErrInvalidConfig, c.TopK)
+	}
+	if c.Pareto < 0 {
+		return fmt.Errorf("%w: pareto must be >= 0 (got %d)", ErrInvalidConfig, c.Pareto)
+	}
+	if c.TurnPenalty < 0 {
+		return fmt.Errorf("%w: turnPenalty must be >= 0 (got %g)", ErrInvalidConfig, c.TurnPenalty)
+	}
+	if c.RiskWeight < 0 || c.HopWeight < 0 {
+		return fmt.Errorf("%w: riskWeight and hopWeight must be >= 0 (got %g, %g)", ErrInvalidConfig, c.RiskWeight, c.HopWeight)
+	}
+	if c.Record < 0 {
+		return fmt.Errorf("%w: record must be >= 0 (got %d)", ErrInvalidConfig, c.Record)
+	}
+	if c.Colonies < 0 || c.ExchangeInterval < 0 {
+		return fmt.Errorf("%w: colonies and exchangeInterval must be >= 0 (got %d, %d)", ErrInvalidConfig, c.Colonies, c.ExchangeInterval)
+	}
+	if c.ExchangeMode != ExchangeBest && c.ExchangeMode != ExchangePheromone {
+		return fmt.Errorf("%w: unknown exchangeMode %q (expected %q or %q)", ErrInvalidConfig, c.ExchangeMode, ExchangeBest, ExchangePheromone)
+	}
+	if c.Workers < 0 {
+		return fmt.Errorf("%w: workers must be >= 0 (got %d)", ErrInvalidConfig, c.Workers)
+	}
+	if c.StagnationLimit < 0 {
+		return fmt.Errorf("%w: stagnationLimit must be >= 0 (got %d)", ErrInvalidConfig, c.StagnationLimit)
+	}
+	switch c.Construction {
+	case ConstructionSimple, ConstructionBacktrack, ConstructionLoopErasure:
+	default:
+		return fmt.Errorf("%w: unknown construction %q (expected %q, %q or %q)", ErrInvalidConfig, c.Construction, ConstructionSimple, ConstructionBacktrack, ConstructionLoopErasure)
+	}
+	if _, ok := randomSources[c.RNG]; !ok {
+		return fmt.Errorf("%w: unknown rng %q (available: %v)", ErrInvalidConfig, c.RNG, RNGNames())
+	}
+	if _, ok := heuristics[c.Heuristic]; !ok {
+		return fmt.Errorf("%w: unknown heuristic %q (available: %v)", ErrInvalidConfig, c.Heuristic, HeuristicNames())
+	}
+	switch c.Variant {
+	case VariantAS:
+	case VariantRank:
+		if c.RankWidth < 1 {
+			return fmt.Errorf("%w: rankWidth must be >= 1 (got %d)", ErrInvalidConfig, c.RankWidth)
+		}
+	default:
+		return fmt.Errorf("%w: unknown variant %q (expected %q or %q)", ErrInvalidConfig, c.Variant, VariantAS, VariantRank)
+	}
+
+	return nil
+}
+
+// EdgePheromone: GraphData.Edges と同じ順序で並ぶ辺ごとのフェロモン量
+type EdgePheromone struct {
+	From  int     `json:"from"`
+	To    int     `json:"to"`
+	Value float64 `json:"value"`
+	// 直近の Step でこの辺を通ったアリの数と、その累計 (無向辺は両向きの合計)
+	Traffic      int `json:"traffic"`
+	TotalTraffic int `json:"totalTraffic"`
+}
+
+// State: getState で返すインスタンスの概要
+type State struct {
+	Seed      int64   `json:"seed"`
+	NodeCount int     `json:"nodeCount"`
+	EdgeCount int     `json:"edgeCount"`
+	StartNode int     `json:"start"`
+	GoalNode  int     `json:"goal"`
+	Goals     []int   `json:"goals,omitempty"`
+	Waypoints []int   `json:"waypoints,omitempty"`
+	Paused    bool    `json:"paused"`
+	BestDist  float64 `json:"bestDist"`
+	BestPath  []int   `json:"bestPath"`
+	Config    Config  `json:"config"`
+}
+
+// Convergence: 収束判定のための指標
+type Convergence struct {
+	Iteration  int     `json:"iteration"`
+	Stagnation int     `json:"stagnation"` // 最後の改善からのイテレーション数
+	Entropy    float64 `json:"entropy"`    // 正規化フェロモンエントロピー
+	Converged  bool    `json:"converged"`
+	// 直近の Step で使った蒸発率 (スケジュール・自動調整の適用後)
+	Evaporation float64 `json:"evaporation"`
+	// 直近の Step で使った α と β (自動調整の適用後。Config.Adaptive は多くのアリが失敗する間 β を上げ、
+	// 改善が止まると α を下げて蒸発率を上げ、改善するたびに戻していく)
+	Alpha float64 `json:"alpha"`
+	Beta  float64 `json:"beta"`
+}
+
+// IterationStats: イテレーションごとの推移 (インデックス i が i+1 回目の Step)
+type IterationStats struct {
+	BestHistory []float64 `json:"bestHistory"` // Step 後のベスト距離
+	SuccessRate []float64 `json:"successRate"` // ゴールできたアリの割合
+	AvgDist     []float64 `json:"avgDist"`     // 成功したアリの平均距離 (成功なしは0)
+	AvgHops     []float64 `json:"avgHops"`     // 成功したアリの平均ノード数 (成功なしは0)
+	Entropy     []float64 `json:"entropy"`     // Step 後のフェロモンエントロピー (PheromoneEntropy)
+	Branching   []float64 `json:"branching"`   // Step 後の λ-branching factor (BranchingLambda)
+	Diversity   []float64 `json:"diversity"`   // アリの経路の多様性 (PathDiversity)
+	DeadEnds    []int     `json:"deadEnds"`    // 行き止まりで進めなくなったアリの数
+	StuckNodes  []int     `json:"stuckNodes"`  // 行き止まりで止まったアリが最も多かったノード (なければ -1)
+	StepLimited []int     `json:"stepLimited"` // ステップ数の上限で打ち切られたアリの数
+	OverBudget  []int     `json:"overBudget"`  // 距離の予算を超えて打ち切られたアリの数
+	Backtracks  []int     `json:"backtracks"`  // アリが行き止まりから引き返した回数の合計
+	Restarts    []Restart `json:"restarts,omitempty"`
+}
+
+// Restart: 停滞によるフェロモンのリスタート
+type Restart struct {
+	Iteration int     `json:"iteration"`
+	BestDist  float64 `json:"bestDist"`         // リスタート時点の大域ベスト
+	Colony    int     `json:"colony,omitempty"` // リスタートしたコロニー (マルチコロニー時)
+}
+
+// PheromoneSummary: 現在の辺ごとのフェロモン量の要約
+type PheromoneSummary struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+}
+
+// Stats: getStats で返す統計
+type Stats struct {
+	Iteration int `json:"iteration"`
+	IterationStats
+	Pheromone PheromoneSummary `json:"pheromone"`
+}
@@ -0,0 +1,54 @@
+package solver
+
+// ノードごとの訪問回数 (可視化のヒートマップ用)
+// recordStats が Step ごとに、アリの経路に現れたノードを数える (ゴールできなかったアリも含む)。
+// 同じアリが同じノードを何度通れば、その回数だけ数える。TSP で最後に戻るスタートは数えない。
+// 累計は Reset で消え、ノードの追加・削除ではノードIDに合わせて伸び縮みする。
+
+// VisitCounts: VisitCounts の結果 (ノードIDの順)
+type VisitCounts struct {
+	Iteration    []int `json:"iteration"` // 直近の Step
+	Total        []int `json:"total"`     // これまでの累計
+	MaxIteration int   `json:"maxIteration"`
+	MaxTotal     int   `json:"maxTotal"`
+}
+
+// VisitCounts: ノードごとの訪問回数 (Step 前は全て 0)
+func (aco *ACO) VisitCounts() VisitCounts {
+	n := len(aco.Graph.Nodes)
+	counts := VisitCounts{Iteration: make([]int, n), Total: make([]int, n)}
+	copy(counts.Iteration, aco.visits)
+	copy(counts.Total, aco.totalVisits)
+	for i := range n {
+		counts.MaxIteration = max(counts.MaxIteration, counts.Iteration[i])
+		counts.MaxTotal = max(counts.MaxTotal, counts.Total[i])
+	}
+	return counts
+}
+
+// countVisits: このイテレーションの訪問回数を数え直し、累計に足す
+func (aco *ACO) countVisits(antResults []AntResult) {
+	n := len(aco.Graph.Nodes)
+	aco.visits = resize(aco.visits, n)
+	for len(aco.totalVisits) < n {
+		aco.totalVisits = append(aco.totalVisits, 0)
+	}
+	for _, result := range antResults {
+		for _, v := range result.Path {
+			aco.visits[v]++
+		}
+	}
+	for v, count := range aco.visits {
+		aco.totalVisits[v] += count
+	}
+}
+
+// removeVisits: ノード id の削除に合わせて訪問回数を詰める
+func (aco *ACO) removeVisits(id int) {
+	if id < len(aco.visits) {
+		aco.visits = append(aco.visits[:id], aco.visits[id+1:]...)
+	}
+	if id < len(aco.totalVisits) {
+		aco.totalVisits = append(aco.totalVisits[:id], aco.totalVisits[id+1:]...)
+	}
+}
@@ -0,0 +1,51 @@
+package solver
+
+import "fmt"
+
+// 経由地 (経路探索モードのみ)
+// アリはスタートから Waypoints を順に通ってゴールへ向かう。
+// 区間ごとに訪問済みをリセットするので、区間をまたいだ同じノードの再訪は許す。
+
+// SetWaypoints: 順に通る経由地を設定する (nil または空で解除)
+func (aco *ACO) SetWaypoints(waypoints []int) error {
+	if len(waypoints) > 0 && aco.Config.Mode != ModeRoute {
+		return fmt.Errorf("%w: waypoints are only supported in %q mode", ErrInvalidConfig, ModeRoute)
+	}
+	for _, id := range waypoints {
+		if err := aco.checkNode(id); err != nil {
+			return err
+		}
+	}
+
+	aco.Waypoints = append([]int(nil), waypoints...)
+	aco.clearBest()
+	return aco.forEachColony(func(colony *ACO) error {
+		return colony.SetWaypoints(waypoints)
+	})
+}
+
+// legTargets: leg 番目の区間の行き先 (最後の区間はいずれかのゴール)
+func (aco *ACO) legTargets(leg int) []int {
+	if leg < len(aco.Waypoints) {
+		return aco.Waypoints[leg : leg+1]
+	}
+	return aco.goals()
+}
+
+// reachedLeg: current が leg 番目の区間の行き先か
+func (aco *ACO) reachedLeg(leg, current int) bool {
+	if leg < len(aco.Waypoints) {
+		return current == aco.Waypoints[leg]
+	}
+	return aco.isGoal(current)
+}
+
+// isWaypoint: id が経由地に含まれるか
+func (aco *ACO) isWaypoint(id int) bool {
+	for _, w := range aco.Waypoints {
+		if w == id {
+			return true
+		}
+	}
+	return false
+}
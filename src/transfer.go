@@ -0,0 +1,265 @@
+//go:build js && wasm
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"syscall/js"
+
+	"cyokozai/explorer-wasmap/solver"
+)
+
+// Transfer modes for respond(), switched with setTransferMode.
+const (
+	TransferJSON    = "json"    // JSON strings (default, caller runs JSON.parse)
+	TransferObject  = "object"  // plain JS objects/arrays built via js.ValueOf
+	TransferMsgPack = "msgpack" // MessagePack bytes in a Uint8Array (no JSON on either side)
+)
+
+var transferMode = TransferJSON
+
+// messageDispatch is set while handleMessage runs a command: its response is
+// forced to JSON so it can be embedded in the envelope, which alone follows
+// the caller's transfer mode.
+var messageDispatch bool
+
+// Error codes carried in the error envelope.
+const (
+	CodeNotInitialized  = "not_initialized"  // unknown handle or initACO not called yet
+	CodeDisposed        = "disposed"         // the instance was released with dispose
+	CodeInvalidArgument = "invalid_argument" // malformed arguments, config or graph
+	CodeInvalidNode     = "invalid_node"     // node index out of range
+	CodeUnreachable     = "unreachable"      // no path between start and goal
+	CodeNegativeCycle   = "negative_cycle"   // a negative-weight cycle makes shortest paths undefined
+	CodeMarshalFailed   = "marshal_failed"   // response could not be encoded
+	CodeInternal        = "internal"
+)
+
+// errNotInitialized is binding-level: the core never sees handles.
+var errNotInitialized = errors.New("not initialized")
+
+// errDisposed reports a handle released with dispose.
+var errDisposed = errors.New("instance disposed")
+
+// errorEnvelope is returned in place of the payload when a call fails.
+type errorEnvelope struct {
+	OK    bool     `json:"ok"`
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// fail logs the error and returns it as an envelope in the current transfer mode.
+func fail(code, message string) interface{} {
+	solver.Logf(solver.LogError, "Error [%s]: %s", code, message)
+
+	return respond(errorEnvelope{Error: apiError{Code: code, Message: message}})
+}
+
+// failErr maps err to its error code via the sentinel errors.
+func failErr(err error) interface{} {
+	return fail(errorCode(err), err.Error())
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, errNotInitialized):
+		return CodeNotInitialized
+	case errors.Is(err, errDisposed):
+		return CodeDisposed
+	case errors.Is(err, solver.ErrInvalidConfig), errors.Is(err, solver.ErrInvalidGraph):
+		return CodeInvalidArgument
+	case errors.Is(err, solver.ErrInvalidNode):
+		return CodeInvalidNode
+	case errors.Is(err, solver.ErrUnreachable):
+		return CodeUnreachable
+	case errors.Is(err, solver.ErrNegativeCycle):
+		return CodeNegativeCycle
+	}
+
+	return CodeInternal
+}
+
+// ok is the response of commands that have no payload.
+func ok() interface{} {
+	return respond(struct {
+		OK bool `json:"ok"`
+	}{OK: true})
+}
+
+// setTransferMode(mode) -> {ok}
+// mode: "json" | "object" | "msgpack". msgpack answers with a Uint8Array holding the
+// MessagePack encoding of the same object (decode it with any MessagePack library).
+// stepACO, startAuto and getPheromones also take a per-call {transfer} option.
+// For per-frame numeric data on large graphs prefer the write* exports, which fill
+// caller-owned Float64Arrays without any encoding.
+func setTransferModeWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return fail(CodeInvalidArgument, "setTransferMode requires a mode string")
+	}
+	mode := args[0].String()
+	if err := checkTransferMode(mode); err != nil {
+		return fail(CodeInvalidArgument, err.Error())
+	}
+	transferMode = mode
+
+	return ok()
+}
+
+// checkTransferMode validates a transfer mode name; "" is accepted as "use the current mode".
+func checkTransferMode(mode string) error {
+	switch mode {
+	case "", TransferJSON, TransferObject, TransferMsgPack:
+		return nil
+	}
+
+	return fmt.Errorf("unknown transfer mode %q", mode)
+}
+
+// respond encodes v according to the current transfer mode.
+func respond(v interface{}) interface{} {
+	return respondAs("", v)
+}
+
+// respondAs encodes v in the given transfer mode, or the current one when mode is "".
+func respondAs(mode string, v interface{}) interface{} {
+	if mode == "" {
+		mode = transferMode
+	}
+	if messageDispatch {
+		mode = TransferJSON
+	}
+	if mode == TransferMsgPack {
+		data, err := marshalMsgPack(v)
+		if err != nil {
+			return fail(CodeMarshalFailed, err.Error())
+		}
+		bytes := js.Global().Get("Uint8Array").New(len(data))
+		js.CopyBytesToJS(bytes, data)
+		return bytes
+	}
+
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		// errorEnvelope itself always marshals, so this cannot recurse
+		return fail(CodeMarshalFailed, err.Error())
+	}
+	if mode == TransferJSON {
+		return string(jsonData)
+	}
+
+	// js.ValueOf only understands map[string]interface{}/[]interface{}/primitives,
+	// so the struct is normalized through its JSON form (keeps the same field names).
+	var generic interface{}
+	if err := json.Unmarshal(jsonData, &generic); err != nil {
+		return fail(CodeMarshalFailed, err.Error())
+	}
+
+	return js.ValueOf(generic)
+}
+
+// writeGraph(nodeBuf, edgeBuf, handle?) -> [nodeValues, edgeValues]
+// nodeBuf receives x,y pairs (2 per node), edgeBuf from,to,weight triples (3 per edge).
+// The returned counts are the required buffer lengths; larger graphs are truncated.
+func writeGraphWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 2)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 2 {
+		return fail(CodeInvalidArgument, "writeGraph requires two Float64Array buffers")
+	}
+
+	nodeValues := make([]float64, 0, 2*len(aco.Graph.Nodes))
+	for _, node := range aco.Graph.Nodes {
+		nodeValues = append(nodeValues, node.X, node.Y)
+	}
+	edgeValues := make([]float64, 0, 3*len(aco.Graph.Edges))
+	for _, e := range aco.Graph.Edges {
+		edgeValues = append(edgeValues, float64(e.From), float64(e.To), e.Weight)
+	}
+
+	if _, err := copyFloat64sToJS(args[0], nodeValues); err != nil {
+		return fail(CodeInvalidArgument, "nodeBuf: "+err.Error())
+	}
+	if _, err := copyFloat64sToJS(args[1], edgeValues); err != nil {
+		return fail(CodeInvalidArgument, "edgeBuf: "+err.Error())
+	}
+
+	return js.ValueOf([]interface{}{len(nodeValues), len(edgeValues)})
+}
+
+// writePheromones(buf, handle?) -> number of edges (required buffer length)
+// buf receives one pheromone value per edge, aligned with getGraph().edges.
+func writePheromonesWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 1 {
+		return fail(CodeInvalidArgument, "writePheromones requires a Float64Array buffer")
+	}
+
+	pheromones := aco.EdgePheromones()
+	values := make([]float64, len(pheromones))
+	for i, p := range pheromones {
+		values[i] = p.Value
+	}
+	written, err := copyFloat64sToJS(args[0], values)
+	if err != nil {
+		return fail(CodeInvalidArgument, err.Error())
+	}
+
+	return written
+}
+
+// writeBestPath(buf, handle?) -> best path length (required buffer length, 0 if none)
+func writeBestPathWrapper(this js.Value, args []js.Value) interface{} {
+	aco, err := lookupACO(args, 1)
+	if err != nil {
+		return failErr(err)
+	}
+	if len(args) < 1 {
+		return fail(CodeInvalidArgument, "writeBestPath requires a Float64Array buffer")
+	}
+
+	values := make([]float64, len(aco.BestPath))
+	for i, id := range aco.BestPath {
+		values[i] = float64(id)
+	}
+	written, err := copyFloat64sToJS(args[0], values)
+	if err != nil {
+		return fail(CodeInvalidArgument, err.Error())
+	}
+
+	return written
+}
+
+// copyFloat64sToJS copies values into the Float64Array dst through a byte view
+// (one boundary crossing instead of one per element). Only as many values as
+// fit are written; it returns len(values) so callers can detect truncation.
+func copyFloat64sToJS(dst js.Value, values []float64) (int, error) {
+	if !dst.InstanceOf(js.Global().Get("Float64Array")) {
+		return 0, errors.New("expected a Float64Array")
+	}
+	count := len(values)
+	if capacity := dst.Length(); capacity < count {
+		count = capacity
+	}
+
+	// Typed arrays use the host byte order, which is little-endian on every wasm host.
+	bytes := make([]byte, count*8)
+	for i := 0; i < count; i++ {
+		binary.LittleEndian.PutUint64(bytes[i*8:], math.Float64bits(values[i]))
+	}
+	view := js.Global().Get("Uint8Array").New(dst.Get("buffer"), dst.Get("byteOffset"), count*8)
+	js.CopyBytesToJS(view, bytes)
+
+	return len(values), nil
+}